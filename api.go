@@ -137,7 +137,7 @@ type Interface interface {
 	// model, but also the driver version. Therefore, updating graphics drivers will invalidate the shader cache.
 	PipelineCache() string
 
-	// Processor creates a new [Processor] for [Compute]. Defines can be boolean and/or numeric values.
+	// Processor creates a new [Processor] for [Compute]. Defines can be boolean and/or numeric values, see [Constants].
 	Processor(shader Shader, defines []any) Processor
 
 	// Renderer creates a new [Renderer] for [Drawing].
@@ -160,6 +160,7 @@ type Interface interface {
 	Shader() Shader
 
 	// SharedTexture creates a shared texture using the specified view and the texture information from 'with'.
+	// The view's FormatOverride must be one of the ShareableFormats of 'with', see [TextureFormat.ValidateView].
 	SharedTexture(view TextureView, with Texture) Texture
 
 	// StorageBuffer creates a storage buffer with the specified data and usage.
@@ -0,0 +1,290 @@
+/*
+Package budget tracks the GPU memory used by a renderer against budgets, so that subsystems (such as texture
+streaming) can react to memory pressure before the driver starts to evict memory.
+
+	budgets := budget.New(RD, 2<<30)
+	streaming := budgets.Subsystem("streaming", 1<<30)
+	streaming.OnPressure(0.8, func(p budget.Pressure) {
+		if p.Rising {
+			dropMipmaps()
+		}
+	})
+	budgets.OnPressure(0.9, func(p budget.Pressure) { log.Println(p) })
+
+	texture := RD.Texture(format, view, data)
+	if err := streaming.Texture(texture); err != nil {
+		streaming.Track(texture, size)
+	}
+	...
+	streaming.Release(texture)
+	texture.Free()
+
+	for running {
+		draw(RD)
+		budgets.Update()
+	}
+
+The device as a whole is measured with [rd.Interface.MemoryUsage], whereas each subsystem is measured by the
+estimated sizes of the resources attributed to it. Pressure callbacks are called by [Manager.Update] whenever
+usage crosses one of their thresholds, in either direction.
+*/
+package budget
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"grow.graphics/rd"
+)
+
+// Manager of the memory budgets of a device.
+type Manager struct {
+	device rd.Interface
+
+	mutex      sync.Mutex
+	budget     int
+	pressure   pressure
+	subsystems map[string]*Subsystem
+	usage      Usage
+}
+
+// New returns a manager for the device, which has a total budget of the given number of bytes.
+func New(device rd.Interface, budget int) *Manager {
+	return &Manager{device: device, budget: budget, subsystems: make(map[string]*Subsystem)}
+}
+
+// Usage of device memory in bytes, see [rd.MemoryType].
+type Usage struct {
+	Textures, Buffers, Total int
+}
+
+// Pressure passed to a callback when usage crosses its threshold.
+type Pressure struct {
+	Subsystem string  // name of the subsystem, empty for the device as a whole.
+	Used      int     // bytes in use.
+	Budget    int     // bytes budgeted.
+	Threshold float64 // fraction of the budget that was crossed.
+	Rising    bool    // true if usage rose above the threshold, false if it fell back below.
+}
+
+func (p Pressure) String() string {
+	name, direction := p.Subsystem, "below"
+	if name == "" {
+		name = "device"
+	}
+	if p.Rising {
+		direction = "above"
+	}
+	return fmt.Sprintf("%s memory %s %.0f%% of budget (%d of %d bytes)", name, direction, p.Threshold*100, p.Used, p.Budget)
+}
+
+// threshold of a pressure callback.
+type threshold struct {
+	fraction float64
+	fn       func(Pressure)
+	above    bool // usage was above the threshold when last checked.
+}
+
+// pressure callbacks of a budget.
+type pressure []threshold
+
+// check the thresholds against the usage, returning the calls to make for those that were crossed.
+func (p pressure) check(name string, used, budget int) []func() {
+	var calls []func()
+	for i := range p {
+		t := &p[i]
+		above := budget > 0 && float64(used) > t.fraction*float64(budget)
+		if above == t.above {
+			continue
+		}
+		t.above = above
+		fn, event := t.fn, Pressure{Subsystem: name, Used: used, Budget: budget, Threshold: t.fraction, Rising: above}
+		calls = append(calls, func() { fn(event) })
+	}
+	return calls
+}
+
+// OnPressure calls fn from [Manager.Update] whenever the total memory usage of the device crosses the given
+// fraction of its budget.
+func (m *Manager) OnPressure(fraction float64, fn func(Pressure)) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.pressure = append(m.pressure, threshold{fraction: fraction, fn: fn})
+}
+
+// SetBudget changes the total budget of the device, in bytes.
+func (m *Manager) SetBudget(budget int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.budget = budget
+}
+
+// Subsystem returns the subsystem with the given name, registering it if needed, with the given budget in bytes.
+func (m *Manager) Subsystem(name string, budget int) *Subsystem {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	s, ok := m.subsystems[name]
+	if !ok {
+		s = &Subsystem{manager: m, name: name, resources: make(map[uint64]int)}
+		m.subsystems[name] = s
+	}
+	s.budget = budget
+	return s
+}
+
+/*
+Update measures the memory usage of the device and calls the pressure callbacks of the device and of each
+subsystem whose usage crossed a threshold since the last update. It should be called once per frame, callbacks
+are called after the manager has been unlocked, so they are free to release resources.
+*/
+func (m *Manager) Update() Usage {
+	usage := Usage{
+		Textures: m.device.MemoryUsage(rd.MemoryTextures),
+		Buffers:  m.device.MemoryUsage(rd.MemoryBuffers),
+		Total:    m.device.MemoryUsage(rd.MemoryTotal),
+	}
+	m.mutex.Lock()
+	m.usage = usage
+	calls := m.pressure.check("", usage.Total, m.budget)
+	for _, s := range m.sorted() {
+		calls = append(calls, s.pressure.check(s.name, s.used, s.budget)...)
+	}
+	m.mutex.Unlock()
+	for _, call := range calls {
+		call()
+	}
+	return usage
+}
+
+// sorted returns the subsystems in order of name.
+func (m *Manager) sorted() []*Subsystem {
+	subsystems := make([]*Subsystem, 0, len(m.subsystems))
+	for _, s := range m.subsystems {
+		subsystems = append(subsystems, s)
+	}
+	sort.Slice(subsystems, func(i, j int) bool { return subsystems[i].name < subsystems[j].name })
+	return subsystems
+}
+
+// Attribution of memory to a subsystem.
+type Attribution struct {
+	Subsystem string
+	Used      int // estimated bytes used by the resources attributed to the subsystem.
+	Budget    int // bytes budgeted.
+	Resources int // number of resources attributed to the subsystem.
+}
+
+// Report of the memory usage of a device, as of the last [Manager.Update].
+type Report struct {
+	Usage
+	Budget       int
+	Subsystems   []Attribution // in order of name.
+	Unattributed int           // bytes of the total usage that are not attributed to any subsystem.
+}
+
+// Report returns the memory usage of the device, as measured by the last [Manager.Update], along with the memory
+// currently attributed to each subsystem.
+func (m *Manager) Report() Report {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	report := Report{Usage: m.usage, Budget: m.budget, Unattributed: m.usage.Total}
+	for _, s := range m.sorted() {
+		report.Subsystems = append(report.Subsystems, Attribution{
+			Subsystem: s.name,
+			Used:      s.used,
+			Budget:    s.budget,
+			Resources: len(s.resources),
+		})
+		report.Unattributed -= s.used
+	}
+	report.Unattributed = max(report.Unattributed, 0)
+	return report
+}
+
+func (r Report) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d of %d bytes (%d textures, %d buffers), %d unattributed", r.Total, r.Budget, r.Textures, r.Buffers, r.Unattributed)
+	for _, s := range r.Subsystems {
+		fmt.Fprintf(&b, "\n%s: %d of %d bytes in %d resources", s.Subsystem, s.Used, s.Budget, s.Resources)
+	}
+	return b.String()
+}
+
+// Subsystem with its own budget, measured by the estimated sizes of the resources attributed to it.
+type Subsystem struct {
+	manager *Manager
+	name    string
+
+	// guarded by the mutex of the manager.
+	budget    int
+	used      int
+	resources map[uint64]int // sizes, by RID.
+	pressure  pressure
+}
+
+// Name of the subsystem.
+func (s *Subsystem) Name() string { return s.name }
+
+// Track attributes the resource to the subsystem, with the given size in bytes. Tracking a resource again
+// replaces its size.
+func (s *Subsystem) Track(resource rd.Resource, size int) {
+	s.manager.mutex.Lock()
+	defer s.manager.mutex.Unlock()
+	rid := resource.RID()
+	s.used += size - s.resources[rid]
+	s.resources[rid] = size
+}
+
+// Texture attributes the texture to the subsystem, with its size estimated from its format, see
+// [rd.TextureFormat.Size]. Returns an error if the size of the format cannot be estimated (such as for
+// multi-planar formats), in which case the texture should be tracked with an explicit size instead.
+func (s *Subsystem) Texture(texture rd.Texture) error {
+	format := texture.Format()
+	size := format.Size()
+	if size == 0 {
+		return fmt.Errorf("budget: cannot estimate the size of a %v texture", format.Format)
+	}
+	s.Track(texture, size)
+	return nil
+}
+
+// Release the attribution of the resource to the subsystem, such as when it is freed.
+func (s *Subsystem) Release(resource rd.Resource) {
+	s.manager.mutex.Lock()
+	defer s.manager.mutex.Unlock()
+	rid := resource.RID()
+	s.used -= s.resources[rid]
+	delete(s.resources, rid)
+}
+
+// Used returns the estimated number of bytes used by the resources attributed to the subsystem.
+func (s *Subsystem) Used() int {
+	s.manager.mutex.Lock()
+	defer s.manager.mutex.Unlock()
+	return s.used
+}
+
+// Budget returns the budget of the subsystem, in bytes.
+func (s *Subsystem) Budget() int {
+	s.manager.mutex.Lock()
+	defer s.manager.mutex.Unlock()
+	return s.budget
+}
+
+// Remaining returns the number of bytes left in the budget of the subsystem, which is negative if the subsystem
+// is over budget.
+func (s *Subsystem) Remaining() int {
+	s.manager.mutex.Lock()
+	defer s.manager.mutex.Unlock()
+	return s.budget - s.used
+}
+
+// OnPressure calls fn from [Manager.Update] whenever the memory attributed to the subsystem crosses the given
+// fraction of its budget.
+func (s *Subsystem) OnPressure(fraction float64, fn func(Pressure)) {
+	s.manager.mutex.Lock()
+	defer s.manager.mutex.Unlock()
+	s.pressure = append(s.pressure, threshold{fraction: fraction, fn: fn})
+}
@@ -0,0 +1,373 @@
+/*
+Package cache provides a persistent on-disk cache of compiled shader binaries, to avoid the cost of compiling
+shaders from source every time a program starts.
+
+Binaries are specific to the GPU model and driver version, so they are stored in a subdirectory named after
+[rd.Interface.PipelineCache], such that devices with different drivers can share the same directory. The
+subdirectories left behind by previous drivers are only removed by [Cache.Prune]. Files are written atomically,
+such that multiple processes can safely share the same cache.
+
+	shaders, err := cache.Open(RD, filepath.Join(dir, "shaders"), 64<<20)
+	if err != nil {
+		return err
+	}
+	shaders.Prune(30 * 24 * time.Hour) // remove the caches of old drivers.
+	shader := shaders.Source("blur", source)
+*/
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"hash/crc32"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"grow.graphics/rd"
+)
+
+// Cache of shader binaries for a particular device.
+type Cache struct {
+	device  rd.Interface
+	parent  string // directory shared with the caches of other devices.
+	dir     string
+	maxSize int64
+
+	mutex sync.Mutex
+	size  int64 // estimated total size of the cached files.
+	stats Stats
+}
+
+// Stats reports on the effectiveness of the cache.
+type Stats struct {
+	Hits      int   // shaders loaded from the cache.
+	Misses    int   // shaders that had to be compiled.
+	Writes    int   // binaries written to the cache.
+	Evictions int   // binaries removed to keep the cache under its size limit.
+	Errors    int   // file system errors, or corrupt files, encountered (the cache falls back to compilation).
+	Size      int64 // total size of the cached binaries, in bytes.
+}
+
+// file header, followed by the binary and then a CRC-32C checksum of the binary.
+const magic = "RDSC"
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// staleTemporary is the age after which a temporary file is assumed to have been left behind by a process
+// that exited while writing it, rather than still being written.
+const staleTemporary = time.Hour
+
+// removeTemporary removes the temporary files in dir that were last modified before the given time.
+func removeTemporary(dir string, before time.Time) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".tmp" {
+			continue
+		}
+		if info, err := entry.Info(); err == nil && info.ModTime().Before(before) {
+			os.Remove(filepath.Join(dir, entry.Name()))
+		}
+	}
+	return nil
+}
+
+/*
+Open the cache within the given directory, which is created if it doesn't exist. If maxSize is positive,
+then the least recently used binaries are evicted whenever the cache grows beyond maxSize bytes. Temporary
+files left behind by processes that exited while writing to the cache are removed.
+*/
+func Open(device rd.Interface, dir string, maxSize int64) (*Cache, error) {
+	uuid := sanitize(device.PipelineCache())
+	if err := os.MkdirAll(filepath.Join(dir, uuid), 0o755); err != nil {
+		return nil, err
+	}
+	c := &Cache{
+		device:  device,
+		parent:  dir,
+		dir:     filepath.Join(dir, uuid),
+		maxSize: maxSize,
+	}
+	if err := removeTemporary(c.dir, time.Now().Add(-staleTemporary)); err != nil {
+		return nil, err
+	}
+	files, err := c.files()
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		c.size += file.size
+	}
+	c.stats.Size = c.size
+	return c, nil
+}
+
+// sanitize the pipeline cache UUID for use as a directory name.
+func sanitize(uuid string) string {
+	clean := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		default:
+			return -1
+		}
+	}, uuid)
+	if clean == "" || clean != uuid {
+		sum := sha256.Sum256([]byte(uuid))
+		clean += hex.EncodeToString(sum[:8])
+	}
+	return clean
+}
+
+// Stats returns the statistics of the cache.
+func (c *Cache) Stats() Stats {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.stats
+}
+
+/*
+Source returns a shader compiled from the given source, using a cached binary if one is available. The
+cache key is a hash of the entire source, so any #define directives (such as those injected by the
+preprocess package) are taken into account.
+*/
+func (c *Cache) Source(name string, source rd.ShaderSource) rd.Shader {
+	hash := sha256.New()
+	var length [9]byte
+	length[0] = byte(source.Language)
+	for _, stage := range [][]byte{source.Compute, source.Fragment, source.TesselationControl, source.TesselationEvaluation, source.Vertex} {
+		binary.LittleEndian.PutUint64(length[1:], uint64(len(stage)))
+		hash.Write(length[:])
+		hash.Write(stage)
+	}
+	return c.compile(name, "src-"+hex.EncodeToString(hash.Sum(nil)), func() []byte {
+		return c.device.CompileSPIRV(name, c.device.CompileSource(true, source))
+	})
+}
+
+// SPIRV returns a shader compiled from the given SPIR-V, using a cached binary if one is available.
+func (c *Cache) SPIRV(name string, spirv rd.SPIRV) rd.Shader {
+	hash := sha256.New()
+	var length [8]byte
+	for _, stage := range [][]byte{spirv.Compute(), spirv.Fragment(), spirv.TesselationControl(), spirv.TesselationEvaluation(), spirv.Vertex()} {
+		binary.LittleEndian.PutUint64(length[:], uint64(len(stage)))
+		hash.Write(length[:])
+		hash.Write(stage)
+	}
+	return c.compile(name, "spv-"+hex.EncodeToString(hash.Sum(nil)), func() []byte {
+		return c.device.CompileSPIRV(name, spirv)
+	})
+}
+
+// compile the shader from its cached binary, or else by calling compile and caching the result. Binaries that
+// are rejected by the device are removed from the cache.
+func (c *Cache) compile(name, key string, compile func() []byte) rd.Shader {
+	if data, ok := c.load(key); ok {
+		if shader := c.device.CompileBinary(data); shader != nil && shader.RID() != 0 {
+			shader.SetResourceName(name)
+			return shader
+		}
+		c.remove(key)
+	}
+	data := compile()
+	if len(data) > 0 {
+		c.store(key, data)
+	}
+	shader := c.device.CompileBinary(data)
+	shader.SetResourceName(name)
+	return shader
+}
+
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, key+".bin")
+}
+
+func (c *Cache) count(stat *int) {
+	c.mutex.Lock()
+	*stat++
+	c.mutex.Unlock()
+}
+
+// load the binary for the given key, returning false if it is missing or corrupt.
+func (c *Cache) load(key string) ([]byte, bool) {
+	file, err := os.ReadFile(c.path(key))
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			c.count(&c.stats.Errors)
+		}
+		c.count(&c.stats.Misses)
+		return nil, false
+	}
+	if len(file) < len(magic)+4 || string(file[:len(magic)]) != magic {
+		c.count(&c.stats.Errors)
+		c.count(&c.stats.Misses)
+		return nil, false
+	}
+	data, checksum := file[len(magic):len(file)-4], binary.LittleEndian.Uint32(file[len(file)-4:])
+	if crc32.Checksum(data, castagnoli) != checksum {
+		c.count(&c.stats.Errors)
+		c.count(&c.stats.Misses)
+		return nil, false
+	}
+	now := time.Now()
+	os.Chtimes(c.path(key), now, now) // for least recently used eviction.
+	c.count(&c.stats.Hits)
+	return data, true
+}
+
+// remove the binary for the given key, after the device rejected it.
+func (c *Cache) remove(key string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.stats.Hits--
+	c.stats.Misses++
+	c.stats.Errors++
+	if info, err := os.Stat(c.path(key)); err == nil && os.Remove(c.path(key)) == nil {
+		c.size -= info.Size()
+		c.stats.Size = c.size
+	}
+}
+
+// store the binary atomically, by writing it to a temporary file and then renaming it.
+func (c *Cache) store(key string, data []byte) {
+	file := make([]byte, 0, len(magic)+len(data)+4)
+	file = append(file, magic...)
+	file = append(file, data...)
+	file = binary.LittleEndian.AppendUint32(file, crc32.Checksum(data, castagnoli))
+	tmp, err := os.CreateTemp(c.dir, key+".*.tmp")
+	if err != nil {
+		c.count(&c.stats.Errors)
+		return
+	}
+	_, err = tmp.Write(file)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), c.path(key))
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		c.count(&c.stats.Errors)
+		return
+	}
+	c.mutex.Lock()
+	c.stats.Writes++
+	c.size += int64(len(file))
+	c.stats.Size = c.size
+	evict := c.maxSize > 0 && c.size > c.maxSize
+	c.mutex.Unlock()
+	if evict {
+		c.evict()
+	}
+}
+
+type cached struct {
+	path     string
+	size     int64
+	accessed time.Time
+}
+
+func (c *Cache) files() ([]cached, error) {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []cached
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".bin" {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue // removed by another process.
+		}
+		files = append(files, cached{
+			path:     filepath.Join(c.dir, entry.Name()),
+			size:     info.Size(),
+			accessed: info.ModTime(),
+		})
+	}
+	return files, nil
+}
+
+// evict the least recently used binaries, until the cache is under its size limit. The directory is
+// rescanned, as other processes may have added or removed files.
+func (c *Cache) evict() {
+	files, err := c.files()
+	if err != nil {
+		c.count(&c.stats.Errors)
+		return
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].accessed.Before(files[j].accessed) })
+	var size int64
+	for _, file := range files {
+		size += file.size
+	}
+	evictions := 0
+	for _, file := range files {
+		if size <= c.maxSize {
+			break
+		}
+		if err := os.Remove(file.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		size -= file.size
+		evictions++
+	}
+	c.mutex.Lock()
+	c.stats.Evictions += evictions
+	c.size = size
+	c.stats.Size = size
+	c.mutex.Unlock()
+}
+
+/*
+Prune removes the caches of other devices (such as those left behind by a previous driver) sharing the same
+directory, that have not been used for the given duration. Only subdirectories that contain nothing but cached
+binaries are considered, anything else in the directory is left alone.
+*/
+func (c *Cache) Prune(unused time.Duration) error {
+	entries, err := os.ReadDir(c.parent)
+	if err != nil {
+		return err
+	}
+	before := time.Now().Add(-unused)
+	var errs []error
+	for _, entry := range entries {
+		dir := filepath.Join(c.parent, entry.Name())
+		if !entry.IsDir() || dir == c.dir || !stale(dir, before) {
+			continue
+		}
+		if err := os.RemoveAll(dir); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// stale reports whether dir looks like the cache of a device, with no files modified since the given time.
+func stale(dir string, before time.Time) bool {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+	for _, entry := range entries {
+		if ext := filepath.Ext(entry.Name()); entry.IsDir() || (ext != ".bin" && ext != ".tmp") {
+			return false
+		}
+		if info, err := entry.Info(); err != nil || !info.ModTime().Before(before) {
+			return false
+		}
+	}
+	return true
+}
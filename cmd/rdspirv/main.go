@@ -0,0 +1,228 @@
+/*
+Command rdspirv dumps the stages of SPIR-V bundles (as written by [spirv.Bundle.WriteTo]) or plain SPIR-V
+modules, along with their reflected interface and a disassembly that is compatible with spirv-dis, so that
+shipped shaders can be inspected without the Khronos tools.
+
+	rdspirv [flags] file...
+
+Reflection is written as comments, so the output for a single stage can be reassembled with spirv-as.
+*/
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strings"
+
+	"grow.graphics/rd/spirv"
+)
+
+var stages = map[string]spirv.ExecutionModel{
+	"vertex":      spirv.ExecutionModelVertex,
+	"tesscontrol": spirv.ExecutionModelTessellationControl,
+	"tesseval":    spirv.ExecutionModelTessellationEvaluation,
+	"geometry":    spirv.ExecutionModelGeometry,
+	"fragment":    spirv.ExecutionModelFragment,
+	"compute":     spirv.ExecutionModelGLCompute,
+}
+
+func main() {
+	var (
+		stage    = flag.String("stage", "", "only dump the given stage (vertex, tesscontrol, tesseval, geometry, fragment or compute)")
+		reflect  = flag.Bool("reflect", true, "dump the reflected interface of each stage")
+		dis      = flag.Bool("dis", true, "dump the disassembly of each stage")
+		rawIDs   = flag.Bool("raw-id", false, "print numeric <id>s rather than friendly names")
+		noHeader = flag.Bool("no-header", false, "omit the module header from the disassembly")
+		noIndent = flag.Bool("no-indent", false, "do not align instructions on their result")
+	)
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: rdspirv [flags] file...\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	only := spirv.ExecutionModel(^uint32(0))
+	if *stage != "" {
+		model, ok := stages[strings.ToLower(*stage)]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "rdspirv: unknown stage %q\n", *stage)
+			os.Exit(2)
+		}
+		only = model
+	}
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+	dump := dumper{
+		out:     out,
+		only:    only,
+		reflect: *reflect,
+		dis:     *dis,
+		options: spirv.DisassembleOptions{RawIDs: *rawIDs, NoHeader: *noHeader, NoIndent: *noIndent},
+	}
+	failed := false
+	for _, name := range flag.Args() {
+		if err := dump.file(name); err != nil {
+			out.Flush()
+			fmt.Fprintf(os.Stderr, "rdspirv: %s: %v\n", name, err)
+			failed = true
+		}
+	}
+	if failed {
+		out.Flush()
+		os.Exit(1)
+	}
+}
+
+type dumper struct {
+	out     io.Writer
+	only    spirv.ExecutionModel
+	reflect bool
+	dis     bool
+	options spirv.DisassembleOptions
+}
+
+// file dumps a bundle or a plain SPIR-V module.
+func (d *dumper) file(name string) error {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return err
+	}
+	bundle, err := load(data)
+	if err != nil {
+		return err
+	}
+	models := make([]spirv.ExecutionModel, 0, len(bundle))
+	for model := range bundle {
+		if d.only == ^spirv.ExecutionModel(0) || d.only == model {
+			models = append(models, model)
+		}
+	}
+	sort.Slice(models, func(i, j int) bool { return models[i] < models[j] })
+	var errs []error
+	for _, model := range models {
+		if err := d.stage(name, model, bundle[model]); err != nil {
+			errs = append(errs, fmt.Errorf("%v: %w", model, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// load a bundle, or wrap a plain SPIR-V module in a bundle according to the execution model of its first entry point.
+func load(data []byte) (spirv.Bundle, error) {
+	if bytes.HasPrefix(data, []byte("RDSV")) {
+		var bundle spirv.Bundle
+		if err := bundle.UnmarshalBinary(data); err != nil {
+			return nil, err
+		}
+		return bundle, nil
+	}
+	words, err := spirv.Words(data)
+	if err != nil {
+		return nil, err
+	}
+	module, err := spirv.ParseWords(words)
+	if err != nil {
+		return nil, err
+	}
+	reflection, err := module.Reflect()
+	if err != nil {
+		return nil, err
+	}
+	if len(reflection.EntryPoints) == 0 {
+		return nil, errors.New("module has no entry points")
+	}
+	return spirv.Bundle{reflection.EntryPoints[0].Model: words}, nil
+}
+
+// stage dumps a single stage of a bundle.
+func (d *dumper) stage(name string, model spirv.ExecutionModel, words []uint32) error {
+	fmt.Fprintf(d.out, "; === %s: %v (%d words) ===\n", name, model, len(words))
+	module, err := spirv.ParseWords(words)
+	if err != nil {
+		return err
+	}
+	if d.reflect {
+		reflection, err := module.Reflect()
+		if err != nil {
+			return err
+		}
+		d.reflection(reflection)
+	}
+	if d.dis {
+		return module.Disassemble(d.out, d.options)
+	}
+	return nil
+}
+
+// reflection dumps the reflected interface of a module as comments.
+func (d *dumper) reflection(r *spirv.Reflection) {
+	for _, ep := range r.EntryPoints {
+		fmt.Fprintf(d.out, "; entry point %q %v", ep.Name, ep.Model)
+		if ep.Model == spirv.ExecutionModelGLCompute {
+			fmt.Fprintf(d.out, " local_size(%d, %d, %d)", ep.WorkgroupSize[0], ep.WorkgroupSize[1], ep.WorkgroupSize[2])
+		}
+		fmt.Fprintln(d.out)
+		for _, in := range ep.Inputs {
+			d.location("input", in)
+		}
+		for _, out := range ep.Outputs {
+			d.location("output", out)
+		}
+	}
+	for _, b := range r.Bindings {
+		count := ""
+		switch {
+		case b.Count == 0:
+			count = "[]"
+		case b.Count > 1:
+			count = fmt.Sprintf("[%d]", b.Count)
+		}
+		fmt.Fprintf(d.out, "; binding set=%d binding=%d %v %v %s%s\n", b.Set, b.Binding, b.Descriptor, b.Type, b.Name, count)
+	}
+	if block := r.PushConstants; block != nil {
+		fmt.Fprintf(d.out, "; push constants %s (%d bytes)\n", block.Name, block.Size)
+		for _, member := range block.Type.Members {
+			fmt.Fprintf(d.out, ";   offset=%d %v %s\n", member.Offset, member.Type, member.Name)
+		}
+	}
+	for _, c := range r.SpecializationConstants {
+		fmt.Fprintf(d.out, "; specialization constant id=%d %v %s = %s\n", c.ID, c.Type, c.Name, value(c))
+	}
+}
+
+func (d *dumper) location(direction string, l spirv.Location) {
+	flat := ""
+	if l.Flat {
+		flat = " flat"
+	}
+	fmt.Fprintf(d.out, ";   %s location=%d component=%d%s %v %s\n", direction, l.Location, l.Component, flat, l.Type, l.Name)
+}
+
+// value formats the default value of a specialization constant.
+func value(c spirv.SpecializationConstant) string {
+	switch c.Type.Kind {
+	case spirv.KindBool:
+		return fmt.Sprint(c.Default != 0)
+	case spirv.KindFloat:
+		if c.Type.Width == 64 {
+			return fmt.Sprint(math.Float64frombits(c.Default))
+		}
+		return fmt.Sprint(math.Float32frombits(uint32(c.Default)))
+	case spirv.KindInt:
+		if c.Type.Signed {
+			shift := 64 - c.Type.Width
+			return fmt.Sprint(int64(c.Default<<shift) >> shift)
+		}
+	}
+	return fmt.Sprint(c.Default)
+}
@@ -0,0 +1,172 @@
+package main
+
+// edit of a line, where op is ' ' for a line present in both a and b, '-' for a line removed from a and '+' for a
+// line added from b.
+type edit struct {
+	op   byte
+	a, b int
+}
+
+/*
+edits returns the shortest edit script from a to b, using the linear space variant of Myers' algorithm, which
+bisects the edit graph at the middle snake of each subproblem, so that large traces can be compared without
+keeping a copy of the search state for every step.
+*/
+func edits(a, b []string) []edit {
+	d := differ{a: a, b: b}
+	d.compare(0, len(a), 0, len(b))
+	return d.script
+}
+
+type differ struct {
+	a, b   []string
+	script []edit
+}
+
+// compare appends the edits from a[a0:a1] to b[b0:b1] to the script.
+func (d *differ) compare(a0, a1, b0, b1 int) {
+	for a0 < a1 && b0 < b1 && d.a[a0] == d.b[b0] {
+		d.script = append(d.script, edit{' ', a0, b0})
+		a0++
+		b0++
+	}
+	suffix := 0
+	for a0 < a1-suffix && b0 < b1-suffix && d.a[a1-suffix-1] == d.b[b1-suffix-1] {
+		suffix++
+	}
+	a1, b1 = a1-suffix, b1-suffix
+	switch {
+	case a0 == a1:
+		for y := b0; y < b1; y++ {
+			d.script = append(d.script, edit{'+', a0, y})
+		}
+	case b0 == b1:
+		for x := a0; x < a1; x++ {
+			d.script = append(d.script, edit{'-', x, b0})
+		}
+	default:
+		if x, y, ok := d.middle(a0, a1, b0, b1); ok {
+			d.compare(a0, x, b0, y)
+			d.compare(x, a1, y, b1)
+			break
+		}
+		for x := a0; x < a1; x++ {
+			d.script = append(d.script, edit{'-', x, b0})
+		}
+		for y := b0; y < b1; y++ {
+			d.script = append(d.script, edit{'+', a1, y})
+		}
+	}
+	for i := 0; i < suffix; i++ {
+		d.script = append(d.script, edit{' ', a1 + i, b1 + i})
+	}
+}
+
+// middle returns a point on the shortest path from (a0, b0) to (a1, b1), found by searching forwards from the
+// start and backwards from the end at the same time, until the two searches overlap.
+func (d *differ) middle(a0, a1, b0, b1 int) (x, y int, ok bool) {
+	var (
+		n, m   = a1 - a0, b1 - b0
+		steps  = (n + m + 1) / 2
+		offset = steps
+		delta  = n - m
+		odd    = delta%2 != 0
+		// furthest x reached on each diagonal k, from the start (forward) and from the end (backward).
+		forward, backward = make([]int, 2*steps+2), make([]int, 2*steps+2)
+		// diagonals that have left the edit graph are excluded from later steps.
+		forwardStart, forwardEnd, backwardStart, backwardEnd int
+	)
+	for k := range forward {
+		forward[k], backward[k] = -1, -1
+	}
+	forward[offset+1], backward[offset+1] = 0, 0
+	for step := 0; step < steps; step++ {
+		for k := -step + forwardStart; k <= step-forwardEnd; k += 2 {
+			var x int
+			if k == -step || (k != step && forward[offset+k-1] < forward[offset+k+1]) {
+				x = forward[offset+k+1]
+			} else {
+				x = forward[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && d.a[a0+x] == d.b[b0+y] {
+				x++
+				y++
+			}
+			forward[offset+k] = x
+			switch {
+			case x > n:
+				forwardEnd += 2
+			case y > m:
+				forwardStart += 2
+			case odd:
+				if i := offset + delta - k; i >= 0 && i < len(backward) && backward[i] != -1 && x >= n-backward[i] {
+					return a0 + x, b0 + y, true
+				}
+			}
+		}
+		for k := -step + backwardStart; k <= step-backwardEnd; k += 2 {
+			var x int
+			if k == -step || (k != step && backward[offset+k-1] < backward[offset+k+1]) {
+				x = backward[offset+k+1]
+			} else {
+				x = backward[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && d.a[a1-x-1] == d.b[b1-y-1] {
+				x++
+				y++
+			}
+			backward[offset+k] = x
+			switch {
+			case x > n:
+				backwardEnd += 2
+			case y > m:
+				backwardStart += 2
+			case !odd:
+				if i := offset + delta - k; i >= 0 && i < len(forward) && forward[i] != -1 && forward[i] >= n-x {
+					fx := forward[i]
+					return a0 + fx, b0 + fx - (i - offset), true
+				}
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// hunk of a unified diff, starting at line a and b.
+type hunk struct {
+	a, b       int
+	aLen, bLen int
+	edits      []edit
+}
+
+// hunks groups the changes of a script, along with the given number of unchanged lines around them.
+func hunks(script []edit, context int) []hunk {
+	var hunks []hunk
+	for i := 0; i < len(script); {
+		if script[i].op == ' ' {
+			i++
+			continue
+		}
+		start, last := max(i-context, 0), i
+		for j := i + 1; j < len(script) && j-last <= 2*context+1; j++ {
+			if script[j].op != ' ' {
+				last = j
+			}
+		}
+		end := min(last+context+1, len(script))
+		h := hunk{a: script[start].a, b: script[start].b, edits: script[start:end]}
+		for _, e := range h.edits {
+			if e.op != '+' {
+				h.aLen++
+			}
+			if e.op != '-' {
+				h.bLen++
+			}
+		}
+		hunks = append(hunks, h)
+		i = end
+	}
+	return hunks
+}
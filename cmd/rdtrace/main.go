@@ -0,0 +1,366 @@
+/*
+Command rdtrace inspects traces captured with [trace.Wrap], so that changes to the performance of a renderer can
+be reviewed without a GPU capture tool.
+
+	rdtrace stats [flags] file
+	rdtrace diff [flags] old new
+
+The stats subcommand breaks a trace down by frame (see [trace.Recorder.Frame]) into the number of draws,
+dispatches and barriers, the bytes uploaded to the device (as the initial data of resources, writes to them and
+push constants) and the number of state changes made within drawing and compute lists, along with how many of
+those were redundant, as they set the state to its current value.
+
+The diff subcommand compares two traces frame by frame, printing the statistics that changed along with a
+unified diff of the calls. Objects are named by [rd.Nameable.SetResourceName], if available, otherwise by the
+method that created them and their position among the objects created by that method, so that an extra
+resource doesn't cause every later call to differ.
+*/
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"grow.graphics/rd/trace"
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage:\n\trdtrace stats [flags] file\n\trdtrace diff [flags] old new\n")
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	out := bufio.NewWriter(os.Stdout)
+	var err error
+	switch command, args := flag.Arg(0), flag.Args()[1:]; command {
+	case "stats":
+		err = stats(out, args)
+	case "diff":
+		err = diff(out, args)
+	default:
+		fmt.Fprintf(os.Stderr, "rdtrace: unknown command %q\n", command)
+		flag.Usage()
+		os.Exit(2)
+	}
+	out.Flush()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "rdtrace: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func stats(out io.Writer, args []string) error {
+	flags := flag.NewFlagSet("stats", flag.ExitOnError)
+	methods := flags.Bool("methods", false, "also print the number of calls to each method")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "usage: rdtrace stats [flags] file\n")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	t, err := load(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "frame\t"+strings.Join(counterNames, "\t")+"\t")
+	var total counters
+	for i, f := range t.frames {
+		fmt.Fprintf(w, "%d\t%s\n", i, f.counters.row())
+		total.add(f.counters)
+	}
+	fmt.Fprintf(w, "total\t%s\n", total.row())
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if *methods {
+		counts := make(map[trace.Method]int)
+		for _, f := range t.frames {
+			for _, c := range f.calls {
+				counts[c.Method]++
+			}
+		}
+		sorted := make([]trace.Method, 0, len(counts))
+		for method := range counts {
+			sorted = append(sorted, method)
+		}
+		sort.Slice(sorted, func(i, j int) bool {
+			if counts[sorted[i]] != counts[sorted[j]] {
+				return counts[sorted[i]] > counts[sorted[j]]
+			}
+			return sorted[i] < sorted[j]
+		})
+		fmt.Fprintln(out)
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		for _, method := range sorted {
+			fmt.Fprintf(w, "%v\t%d\n", method, counts[method])
+		}
+		return w.Flush()
+	}
+	return nil
+}
+
+func diff(out io.Writer, args []string) error {
+	flags := flag.NewFlagSet("diff", flag.ExitOnError)
+	context := flags.Int("context", 3, "number of unchanged calls to print around each change")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "usage: rdtrace diff [flags] old new\n")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 2 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	before, err := load(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	after, err := load(flags.Arg(1))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "--- %s\n+++ %s\n", flags.Arg(0), flags.Arg(1))
+	for i := 0; i < max(len(before.frames), len(after.frames)); i++ {
+		var a, b frame
+		if i < len(before.frames) {
+			a = before.frames[i]
+		}
+		if i < len(after.frames) {
+			b = after.frames[i]
+		}
+		changed := a.counters.changes(b.counters)
+		hunks := hunks(edits(a.lines, b.lines), *context)
+		if len(changed) == 0 && len(hunks) == 0 {
+			continue
+		}
+		fmt.Fprintf(out, "frame %d:", i)
+		if i >= len(before.frames) {
+			fmt.Fprint(out, " added")
+		} else if i >= len(after.frames) {
+			fmt.Fprint(out, " removed")
+		}
+		if len(changed) > 0 {
+			fmt.Fprint(out, " "+strings.Join(changed, ", "))
+		}
+		fmt.Fprintln(out)
+		for _, h := range hunks {
+			fmt.Fprintf(out, "@@ -%d,%d +%d,%d @@\n", h.a+1, h.aLen, h.b+1, h.bLen)
+			for _, e := range h.edits {
+				switch e.op {
+				case ' ', '-':
+					fmt.Fprintf(out, "%c%s\n", e.op, a.lines[e.a])
+				case '+':
+					fmt.Fprintf(out, "%c%s\n", e.op, b.lines[e.b])
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// loaded trace.
+type loaded struct {
+	frames []frame
+}
+
+type frame struct {
+	calls    []trace.Call
+	lines    []string // calls, formatted with objects named.
+	counters counters
+}
+
+// counters of a frame.
+type counters struct {
+	calls, draws, dispatches, barriers, uploaded, state, redundant, created, freed int
+}
+
+var counterNames = []string{"calls", "draws", "dispatches", "barriers", "uploaded", "state", "redundant", "created", "freed"}
+
+func (c *counters) values() []int {
+	return []int{c.calls, c.draws, c.dispatches, c.barriers, c.uploaded, c.state, c.redundant, c.created, c.freed}
+}
+
+func (c *counters) row() string {
+	var b strings.Builder
+	for _, v := range c.values() {
+		b.WriteString(strconv.Itoa(v))
+		b.WriteByte('\t')
+	}
+	return b.String()
+}
+
+func (c *counters) add(o counters) {
+	c.calls += o.calls
+	c.draws += o.draws
+	c.dispatches += o.dispatches
+	c.barriers += o.barriers
+	c.uploaded += o.uploaded
+	c.state += o.state
+	c.redundant += o.redundant
+	c.created += o.created
+	c.freed += o.freed
+}
+
+// changes returns the counters that differ, such as "draws 10 -> 12".
+func (c *counters) changes(o counters) []string {
+	var changes []string
+	for i, a := range c.values() {
+		if b := o.values()[i]; a != b {
+			changes = append(changes, fmt.Sprintf("%s %d -> %d", counterNames[i], a, b))
+		}
+	}
+	return changes
+}
+
+// stateMethods are the methods that change the state of a drawing or compute list.
+var stateMethods = map[trace.Method]bool{
+	trace.MethodDrawingSetBlendConstant: true,
+	trace.MethodDrawingSetData:          true,
+	trace.MethodDrawingSetIndexArray:    true,
+	trace.MethodDrawingSetRenderer:      true,
+	trace.MethodDrawingSetScissor:       true,
+	trace.MethodDrawingSetVariables:     true,
+	trace.MethodDrawingSetVertexArray:   true,
+	trace.MethodComputeSetData:          true,
+	trace.MethodComputeSetProcessor:     true,
+	trace.MethodComputeSetVariables:     true,
+}
+
+// uploadMethods are the methods whose byte arguments are uploaded to the device.
+var uploadMethods = map[trace.Method]bool{
+	trace.MethodIndexBufferU16: true,
+	trace.MethodIndexBufferU32: true,
+	trace.MethodStorageBuffer:  true,
+	trace.MethodTexture:        true,
+	trace.MethodTextureBuffer:  true,
+	trace.MethodUniformBuffer:  true,
+	trace.MethodVertexBuffer:   true,
+	trace.MethodTextureWrite:   true,
+	trace.MethodBufferWriteAt:  true,
+	trace.MethodDrawingSetData: true,
+	trace.MethodComputeSetData: true,
+}
+
+// load a trace, breaking it down into frames.
+func load(path string) (*loaded, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	reader, err := trace.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	var (
+		t       loaded
+		current frame
+		names   = make(map[uint64]string)
+		created = make(map[trace.Method]int)
+		lists   = make(map[uint64]map[string]string) // state of each list, by method and level.
+	)
+	name := func(ref trace.Ref) string {
+		if name, ok := names[uint64(ref)]; ok {
+			return name
+		}
+		return ref.String()
+	}
+	for {
+		c, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if c.Method == trace.MethodFrame {
+			t.frames = append(t.frames, current)
+			current = frame{}
+			continue
+		}
+		count := &current.counters
+		count.calls++
+		switch c.Method {
+		case trace.MethodDrawingSubmit:
+			count.draws++
+		case trace.MethodComputeSubmit:
+			count.dispatches++
+		case trace.MethodBarrier, trace.MethodBarrierFull:
+			count.barriers++
+		case trace.MethodFree:
+			count.freed++
+		case trace.MethodDrawing, trace.MethodDrawingOnScreen, trace.MethodCompute:
+			lists[c.Result] = make(map[string]string)
+		case trace.MethodDrawingEnd, trace.MethodComputeEnd:
+			delete(lists, c.Object)
+		}
+		if uploadMethods[c.Method] {
+			count.uploaded += uploaded(c.Args)
+		}
+		if c.Result != 0 {
+			count.created++
+			created[c.Method]++
+			names[c.Result] = fmt.Sprintf("%v#%d", c.Method, created[c.Method])
+		}
+		line := c.Format(name)
+		if state, ok := lists[c.Object]; ok {
+			// commands are named by their list.
+			c.Object = 0
+			line = "\t" + c.Format(name)
+			if stateMethods[c.Method] {
+				key := c.Method.String()
+				if (c.Method == trace.MethodDrawingSetVariables || c.Method == trace.MethodComputeSetVariables) && len(c.Args) == 2 {
+					key += trace.Format(c.Args[0])
+				}
+				value := line
+				if state[key] == value {
+					count.redundant++
+				} else {
+					count.state++
+					state[key] = value
+				}
+			}
+		}
+		if c.Method == trace.MethodSetResourceName && len(c.Args) == 1 {
+			if s, ok := c.Args[0].(string); ok {
+				names[c.Object] = strconv.Quote(s)
+			}
+		}
+		current.calls = append(current.calls, c)
+		current.lines = append(current.lines, line)
+	}
+	if len(current.calls) > 0 {
+		t.frames = append(t.frames, current)
+	}
+	return &t, nil
+}
+
+// uploaded returns the number of bytes within the value.
+func uploaded(value any) int {
+	switch v := value.(type) {
+	case []byte:
+		return len(v)
+	case []any:
+		n := 0
+		for _, elem := range v {
+			n += uploaded(elem)
+		}
+		return n
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,207 @@
+package rd
+
+import (
+	"slices"
+	"sync"
+
+	"grow.graphics/uc"
+	"grow.graphics/xy"
+)
+
+/*
+CommandBuffer records [Interface.Drawing] and [Interface.Compute] operations without a rendering device, so
+that they can be replayed into one later with [CommandBuffer.Replay]. This allows scene traversal to be
+split across goroutines, each recording into its own command buffer, with the buffers replayed in a
+chosen order on the goroutine that owns the device.
+
+	buffers := make([]rd.CommandBuffer, len(chunks))
+	var wg sync.WaitGroup
+	for i, chunk := range chunks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			buffers[i].Drawing(frame, func(drawing rd.Drawing) {
+				for _, mesh := range chunk {
+					mesh.Draw(drawing)
+				}
+			})
+		}()
+	}
+	wg.Wait()
+	for i := range buffers {
+		buffers[i].Replay(RD)
+	}
+
+A command buffer is safe for concurrent use, each operation is recorded atomically, however operations that
+are recorded concurrently into the same command buffer are replayed in an unspecified order. The zero value
+is an empty command buffer, ready to use.
+
+Values passed to the recorded commands are retained until the command buffer is reset, except for the data
+passed to SetData and the region passed to SetScissor, which are copied. The [Frame] is captured when the
+operation is recorded.
+*/
+type CommandBuffer struct {
+	mutex      sync.Mutex
+	operations []operation
+}
+
+// operation recorded by a [CommandBuffer], either drawing or compute.
+type operation struct {
+	frame     Frame
+	isDrawing bool
+	drawing   []func(Drawing)
+	compute   []func(Compute)
+}
+
+// Drawing records a drawing operation, see [Interface.Drawing]. fn is called immediately, with a [Drawing]
+// that records the commands.
+func (cb *CommandBuffer) Drawing(frame Frame, fn func(Drawing)) {
+	frame.Storage = slices.Clone(frame.Storage)
+	frame.Clear.Colors = slices.Clone(frame.Clear.Colors)
+	recorder := new(drawingRecorder)
+	fn(recorder)
+	cb.record(operation{frame: frame, isDrawing: true, drawing: recorder.commands})
+}
+
+// Compute records a compute operation, see [Interface.Compute]. fn is called immediately, with a [Compute]
+// that records the commands.
+func (cb *CommandBuffer) Compute(fn func(Compute)) {
+	recorder := new(computeRecorder)
+	fn(recorder)
+	cb.record(operation{compute: recorder.commands})
+}
+
+func (cb *CommandBuffer) record(op operation) {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+	cb.operations = append(cb.operations, op)
+}
+
+// Len returns the number of operations recorded since the command buffer was last reset.
+func (cb *CommandBuffer) Len() int {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+	return len(cb.operations)
+}
+
+// Replay the recorded operations into the device, in the order that they were recorded. The command buffer
+// may be replayed any number of times.
+func (cb *CommandBuffer) Replay(device Interface) {
+	cb.mutex.Lock()
+	operations := slices.Clone(cb.operations)
+	cb.mutex.Unlock()
+	for _, op := range operations {
+		if op.isDrawing {
+			device.Drawing(op.frame, func(drawing Drawing) {
+				for _, command := range op.drawing {
+					command(drawing)
+				}
+			})
+			continue
+		}
+		device.Compute(func(compute Compute) {
+			for _, command := range op.compute {
+				command(compute)
+			}
+		})
+	}
+}
+
+// Reset discards the recorded operations, so that the command buffer can be reused.
+func (cb *CommandBuffer) Reset() {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+	clear(cb.operations)
+	cb.operations = cb.operations[:0]
+}
+
+// drawingRecorder implements [Drawing] by recording each command.
+type drawingRecorder struct {
+	commands []func(Drawing)
+}
+
+func (r *drawingRecorder) add(command func(Drawing)) { r.commands = append(r.commands, command) }
+
+func (r *drawingRecorder) DebugBlock(name string, color uc.Color, block func()) {
+	outer := r.commands
+	r.commands = nil
+	block()
+	inner := r.commands
+	r.commands = outer
+	r.add(func(d Drawing) {
+		d.DebugBlock(name, color, func() {
+			for _, command := range inner {
+				command(d)
+			}
+		})
+	})
+}
+
+func (r *drawingRecorder) DebugLabel(name string, color uc.Color) {
+	r.add(func(d Drawing) { d.DebugLabel(name, color) })
+}
+
+func (r *drawingRecorder) SetBlendConstant(color uc.Color) {
+	r.add(func(d Drawing) { d.SetBlendConstant(color) })
+}
+
+func (r *drawingRecorder) SetData(data []byte) {
+	data = slices.Clone(data)
+	r.add(func(d Drawing) { d.SetData(data) })
+}
+
+func (r *drawingRecorder) SetIndexArray(array IndexArray) {
+	r.add(func(d Drawing) { d.SetIndexArray(array) })
+}
+
+func (r *drawingRecorder) SetRenderer(renderer Renderer) {
+	r.add(func(d Drawing) { d.SetRenderer(renderer) })
+}
+
+func (r *drawingRecorder) SetScissor(region *xy.Rect2) {
+	if region != nil {
+		copied := *region
+		region = &copied
+	}
+	r.add(func(d Drawing) { d.SetScissor(region) })
+}
+
+func (r *drawingRecorder) SetVariables(level VariableLevel, variables Variables) {
+	r.add(func(d Drawing) { d.SetVariables(level, variables) })
+}
+
+func (r *drawingRecorder) SetVertexArray(array VertexArray) {
+	r.add(func(d Drawing) { d.SetVertexArray(array) })
+}
+
+func (r *drawingRecorder) Submit(indices bool, instances, vertices int) {
+	r.add(func(d Drawing) { d.Submit(indices, instances, vertices) })
+}
+
+func (r *drawingRecorder) SwitchToNextPass() {
+	r.add(func(d Drawing) { d.SwitchToNextPass() })
+}
+
+// computeRecorder implements [Compute] by recording each command.
+type computeRecorder struct {
+	commands []func(Compute)
+}
+
+func (r *computeRecorder) add(command func(Compute)) { r.commands = append(r.commands, command) }
+
+func (r *computeRecorder) SetData(data []byte) {
+	data = slices.Clone(data)
+	r.add(func(c Compute) { c.SetData(data) })
+}
+
+func (r *computeRecorder) SetProcessor(p Processor) {
+	r.add(func(c Compute) { c.SetProcessor(p) })
+}
+
+func (r *computeRecorder) SetVariables(level VariableLevel, variables Variables) {
+	r.add(func(c Compute) { c.SetVariables(level, variables) })
+}
+
+func (r *computeRecorder) Submit(x, y, z int) {
+	r.add(func(c Compute) { c.Submit(x, y, z) })
+}
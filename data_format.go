@@ -1,5 +1,10 @@
 package rd
 
+import (
+	"strconv"
+	"sync"
+)
+
 // DataFormat for texture.
 type DataFormat int
 
@@ -224,3 +229,441 @@ const (
 	DataFormat_G16_B16_R16_3PLANE_444_UNORM
 	DataFormatDefault
 )
+
+// String returns the name of the format, as used by Vulkan (without the VK_FORMAT_ prefix).
+func (f DataFormat) String() string {
+	if f < 0 || f >= DataFormat(len(dataFormatNames)) {
+		if f == DataFormatDefault {
+			return "DEFAULT"
+		}
+		return "DataFormat(" + strconv.Itoa(int(f)) + ")"
+	}
+	return dataFormatNames[f]
+}
+
+var dataFormatNames = [...]string{
+	DataFormat_R4G4_UNORM_PACK8:                           "R4G4_UNORM_PACK8",
+	DataFormat_R4G4B4A4_UNORM_PACK16:                      "R4G4B4A4_UNORM_PACK16",
+	DataFormat_B4G4R4A4_UNORM_PACK16:                      "B4G4R4A4_UNORM_PACK16",
+	DataFormat_R5G6B5_UNORM_PACK16:                        "R5G6B5_UNORM_PACK16",
+	DataFormat_B5G6R5_UNORM_PACK16:                        "B5G6R5_UNORM_PACK16",
+	DataFormat_R5G5B5A1_UNORM_PACK16:                      "R5G5B5A1_UNORM_PACK16",
+	DataFormat_B5G5R5A1_UNORM_PACK16:                      "B5G5R5A1_UNORM_PACK16",
+	DataFormat_A1R5G5B5_UNORM_PACK16:                      "A1R5G5B5_UNORM_PACK16",
+	DataFormat_R8_UNORM:                                   "R8_UNORM",
+	DataFormat_R8_SNORM:                                   "R8_SNORM",
+	DataFormat_R8_USCALED:                                 "R8_USCALED",
+	DataFormat_R8_SSCALED:                                 "R8_SSCALED",
+	DataFormat_R8_UINT:                                    "R8_UINT",
+	DataFormat_R8_SINT:                                    "R8_SINT",
+	DataFormat_R8_SRGB:                                    "R8_SRGB",
+	DataFormat_R8G8_UNORM:                                 "R8G8_UNORM",
+	DataFormat_R8G8_SNORM:                                 "R8G8_SNORM",
+	DataFormat_R8G8_USCALED:                               "R8G8_USCALED",
+	DataFormat_R8G8_SSCALED:                               "R8G8_SSCALED",
+	DataFormat_R8G8_UINT:                                  "R8G8_UINT",
+	DataFormat_R8G8_SINT:                                  "R8G8_SINT",
+	DataFormat_R8G8_SRGB:                                  "R8G8_SRGB",
+	DataFormat_R8G8B8_UNORM:                               "R8G8B8_UNORM",
+	DataFormat_R8G8B8_SNORM:                               "R8G8B8_SNORM",
+	DataFormat_R8G8B8_USCALED:                             "R8G8B8_USCALED",
+	DataFormat_R8G8B8_SSCALED:                             "R8G8B8_SSCALED",
+	DataFormat_R8G8B8_UINT:                                "R8G8B8_UINT",
+	DataFormat_R8G8B8_SINT:                                "R8G8B8_SINT",
+	DataFormat_R8G8B8_SRGB:                                "R8G8B8_SRGB",
+	DataFormat_B8G8R8_UNORM:                               "B8G8R8_UNORM",
+	DataFormat_B8G8R8_SNORM:                               "B8G8R8_SNORM",
+	DataFormat_B8G8R8_USCALED:                             "B8G8R8_USCALED",
+	DataFormat_B8G8R8_SSCALED:                             "B8G8R8_SSCALED",
+	DataFormat_B8G8R8_UINT:                                "B8G8R8_UINT",
+	DataFormat_B8G8R8_SINT:                                "B8G8R8_SINT",
+	DataFormat_B8G8R8_SRGB:                                "B8G8R8_SRGB",
+	DataFormat_R8G8B8A8_UNORM:                             "R8G8B8A8_UNORM",
+	DataFormat_R8G8B8A8_SNORM:                             "R8G8B8A8_SNORM",
+	DataFormat_R8G8B8A8_USCALED:                           "R8G8B8A8_USCALED",
+	DataFormat_R8G8B8A8_SSCALED:                           "R8G8B8A8_SSCALED",
+	DataFormat_R8G8B8A8_UINT:                              "R8G8B8A8_UINT",
+	DataFormat_R8G8B8A8_SINT:                              "R8G8B8A8_SINT",
+	DataFormat_R8G8B8A8_SRGB:                              "R8G8B8A8_SRGB",
+	DataFormat_B8G8R8A8_UNORM:                             "B8G8R8A8_UNORM",
+	DataFormat_B8G8R8A8_SNORM:                             "B8G8R8A8_SNORM",
+	DataFormat_B8G8R8A8_USCALED:                           "B8G8R8A8_USCALED",
+	DataFormat_B8G8R8A8_SSCALED:                           "B8G8R8A8_SSCALED",
+	DataFormat_B8G8R8A8_UINT:                              "B8G8R8A8_UINT",
+	DataFormat_B8G8R8A8_SINT:                              "B8G8R8A8_SINT",
+	DataFormat_B8G8R8A8_SRGB:                              "B8G8R8A8_SRGB",
+	DataFormat_A8B8G8R8_UNORM_PACK32:                      "A8B8G8R8_UNORM_PACK32",
+	DataFormat_A8B8G8R8_SNORM_PACK32:                      "A8B8G8R8_SNORM_PACK32",
+	DataFormat_A8B8G8R8_USCALED_PACK32:                    "A8B8G8R8_USCALED_PACK32",
+	DataFormat_A8B8G8R8_SSCALED_PACK32:                    "A8B8G8R8_SSCALED_PACK32",
+	DataFormat_A8B8G8R8_UINT_PACK32:                       "A8B8G8R8_UINT_PACK32",
+	DataFormat_A8B8G8R8_SINT_PACK32:                       "A8B8G8R8_SINT_PACK32",
+	DataFormat_A8B8G8R8_SRGB_PACK32:                       "A8B8G8R8_SRGB_PACK32",
+	DataFormat_A2R10G10B10_UNORM_PACK32:                   "A2R10G10B10_UNORM_PACK32",
+	DataFormat_A2R10G10B10_SNORM_PACK32:                   "A2R10G10B10_SNORM_PACK32",
+	DataFormat_A2R10G10B10_USCALED_PACK32:                 "A2R10G10B10_USCALED_PACK32",
+	DataFormat_A2R10G10B10_SSCALED_PACK32:                 "A2R10G10B10_SSCALED_PACK32",
+	DataFormat_A2R10G10B10_UINT_PACK32:                    "A2R10G10B10_UINT_PACK32",
+	DataFormat_A2R10G10B10_SINT_PACK32:                    "A2R10G10B10_SINT_PACK32",
+	DataFormat_A2B10G10R10_UNORM_PACK32:                   "A2B10G10R10_UNORM_PACK32",
+	DataFormat_A2B10G10R10_SNORM_PACK32:                   "A2B10G10R10_SNORM_PACK32",
+	DataFormat_A2B10G10R10_USCALED_PACK32:                 "A2B10G10R10_USCALED_PACK32",
+	DataFormat_A2B10G10R10_SSCALED_PACK32:                 "A2B10G10R10_SSCALED_PACK32",
+	DataFormat_A2B10G10R10_UINT_PACK32:                    "A2B10G10R10_UINT_PACK32",
+	DataFormat_A2B10G10R10_SINT_PACK32:                    "A2B10G10R10_SINT_PACK32",
+	DataFormat_R16_UNORM:                                  "R16_UNORM",
+	DataFormat_R16_SNORM:                                  "R16_SNORM",
+	DataFormat_R16_USCALED:                                "R16_USCALED",
+	DataFormat_R16_SSCALED:                                "R16_SSCALED",
+	DataFormat_R16_UINT:                                   "R16_UINT",
+	DataFormat_R16_SINT:                                   "R16_SINT",
+	DataFormat_R16_SFLOAT:                                 "R16_SFLOAT",
+	DataFormat_R16G16_UNORM:                               "R16G16_UNORM",
+	DataFormat_R16G16_SNORM:                               "R16G16_SNORM",
+	DataFormat_R16G16_USCALED:                             "R16G16_USCALED",
+	DataFormat_R16G16_SSCALED:                             "R16G16_SSCALED",
+	DataFormat_R16G16_UINT:                                "R16G16_UINT",
+	DataFormat_R16G16_SINT:                                "R16G16_SINT",
+	DataFormat_R16G16_SFLOAT:                              "R16G16_SFLOAT",
+	DataFormat_R16G16B16_UNORM:                            "R16G16B16_UNORM",
+	DataFormat_R16G16B16_SNORM:                            "R16G16B16_SNORM",
+	DataFormat_R16G16B16_USCALED:                          "R16G16B16_USCALED",
+	DataFormat_R16G16B16_SSCALED:                          "R16G16B16_SSCALED",
+	DataFormat_R16G16B16_UINT:                             "R16G16B16_UINT",
+	DataFormat_R16G16B16_SINT:                             "R16G16B16_SINT",
+	DataFormat_R16G16B16_SFLOAT:                           "R16G16B16_SFLOAT",
+	DataFormat_R16G16B16A16_UNORM:                         "R16G16B16A16_UNORM",
+	DataFormat_R16G16B16A16_SNORM:                         "R16G16B16A16_SNORM",
+	DataFormat_R16G16B16A16_USCALED:                       "R16G16B16A16_USCALED",
+	DataFormat_R16G16B16A16_SSCALED:                       "R16G16B16A16_SSCALED",
+	DataFormat_R16G16B16A16_UINT:                          "R16G16B16A16_UINT",
+	DataFormat_R16G16B16A16_SINT:                          "R16G16B16A16_SINT",
+	DataFormat_R16G16B16A16_SFLOAT:                        "R16G16B16A16_SFLOAT",
+	DataFormat_R32_UINT:                                   "R32_UINT",
+	DataFormat_R32_SINT:                                   "R32_SINT",
+	DataFormat_R32_SFLOAT:                                 "R32_SFLOAT",
+	DataFormat_R32G32_UINT:                                "R32G32_UINT",
+	DataFormat_R32G32_SINT:                                "R32G32_SINT",
+	DataFormat_R32G32_SFLOAT:                              "R32G32_SFLOAT",
+	DataFormat_R32G32B32_UINT:                             "R32G32B32_UINT",
+	DataFormat_R32G32B32_SINT:                             "R32G32B32_SINT",
+	DataFormat_R32G32B32_SFLOAT:                           "R32G32B32_SFLOAT",
+	DataFormat_R32G32B32A32_UINT:                          "R32G32B32A32_UINT",
+	DataFormat_R32G32B32A32_SINT:                          "R32G32B32A32_SINT",
+	DataFormat_R32G32B32A32_SFLOAT:                        "R32G32B32A32_SFLOAT",
+	DataFormat_R64_UINT:                                   "R64_UINT",
+	DataFormat_R64_SINT:                                   "R64_SINT",
+	DataFormat_R64_SFLOAT:                                 "R64_SFLOAT",
+	DataFormat_R64G64_UINT:                                "R64G64_UINT",
+	DataFormat_R64G64_SINT:                                "R64G64_SINT",
+	DataFormat_R64G64_SFLOAT:                              "R64G64_SFLOAT",
+	DataFormat_R64G64B64_UINT:                             "R64G64B64_UINT",
+	DataFormat_R64G64B64_SINT:                             "R64G64B64_SINT",
+	DataFormat_R64G64B64_SFLOAT:                           "R64G64B64_SFLOAT",
+	DataFormat_R64G64B64A64_UINT:                          "R64G64B64A64_UINT",
+	DataFormat_R64G64B64A64_SINT:                          "R64G64B64A64_SINT",
+	DataFormat_R64G64B64A64_SFLOAT:                        "R64G64B64A64_SFLOAT",
+	DataFormat_B10G11R11_UFLOAT_PACK32:                    "B10G11R11_UFLOAT_PACK32",
+	DataFormat_E5B9G9R9_UFLOAT_PACK32:                     "E5B9G9R9_UFLOAT_PACK32",
+	DataFormat_D16_UNORM:                                  "D16_UNORM",
+	DataFormat_X8_D24_UNORM_PACK32:                        "X8_D24_UNORM_PACK32",
+	DataFormat_D32_SFLOAT:                                 "D32_SFLOAT",
+	DataFormat_S8_UINT:                                    "S8_UINT",
+	DataFormat_D16_UNORM_S8_UINT:                          "D16_UNORM_S8_UINT",
+	DataFormat_D24_UNORM_S8_UINT:                          "D24_UNORM_S8_UINT",
+	DataFormat_D32_SFLOAT_S8_UINT:                         "D32_SFLOAT_S8_UINT",
+	DataFormat_BC1_RGB_UNORM_BLOCK:                        "BC1_RGB_UNORM_BLOCK",
+	DataFormat_BC1_RGB_SRGB_BLOCK:                         "BC1_RGB_SRGB_BLOCK",
+	DataFormat_BC1_RGBA_UNORM_BLOCK:                       "BC1_RGBA_UNORM_BLOCK",
+	DataFormat_BC1_RGBA_SRGB_BLOCK:                        "BC1_RGBA_SRGB_BLOCK",
+	DataFormat_BC2_UNORM_BLOCK:                            "BC2_UNORM_BLOCK",
+	DataFormat_BC2_SRGB_BLOCK:                             "BC2_SRGB_BLOCK",
+	DataFormat_BC3_UNORM_BLOCK:                            "BC3_UNORM_BLOCK",
+	DataFormat_BC3_SRGB_BLOCK:                             "BC3_SRGB_BLOCK",
+	DataFormat_BC4_UNORM_BLOCK:                            "BC4_UNORM_BLOCK",
+	DataFormat_BC4_SNORM_BLOCK:                            "BC4_SNORM_BLOCK",
+	DataFormat_BC5_UNORM_BLOCK:                            "BC5_UNORM_BLOCK",
+	DataFormat_BC5_SNORM_BLOCK:                            "BC5_SNORM_BLOCK",
+	DataFormat_BC6H_UFLOAT_BLOCK:                          "BC6H_UFLOAT_BLOCK",
+	DataFormat_BC6H_SFLOAT_BLOCK:                          "BC6H_SFLOAT_BLOCK",
+	DataFormat_BC7_UNORM_BLOCK:                            "BC7_UNORM_BLOCK",
+	DataFormat_BC7_SRGB_BLOCK:                             "BC7_SRGB_BLOCK",
+	DataFormat_ETC2_R8G8B8_UNORM_BLOCK:                    "ETC2_R8G8B8_UNORM_BLOCK",
+	DataFormat_ETC2_R8G8B8_SRGB_BLOCK:                     "ETC2_R8G8B8_SRGB_BLOCK",
+	DataFormat_ETC2_R8G8B8A1_UNORM_BLOCK:                  "ETC2_R8G8B8A1_UNORM_BLOCK",
+	DataFormat_ETC2_R8G8B8A1_SRGB_BLOCK:                   "ETC2_R8G8B8A1_SRGB_BLOCK",
+	DataFormat_ETC2_R8G8B8A8_UNORM_BLOCK:                  "ETC2_R8G8B8A8_UNORM_BLOCK",
+	DataFormat_ETC2_R8G8B8A8_SRGB_BLOCK:                   "ETC2_R8G8B8A8_SRGB_BLOCK",
+	DataFormat_EAC_R11_UNORM_BLOCK:                        "EAC_R11_UNORM_BLOCK",
+	DataFormat_EAC_R11_SNORM_BLOCK:                        "EAC_R11_SNORM_BLOCK",
+	DataFormat_EAC_R11G11_UNORM_BLOCK:                     "EAC_R11G11_UNORM_BLOCK",
+	DataFormat_EAC_R11G11_SNORM_BLOCK:                     "EAC_R11G11_SNORM_BLOCK",
+	DataFormat_ASTC_4x4_UNORM_BLOCK:                       "ASTC_4x4_UNORM_BLOCK",
+	DataFormat_ASTC_4x4_SRGB_BLOCK:                        "ASTC_4x4_SRGB_BLOCK",
+	DataFormat_ASTC_5x4_UNORM_BLOCK:                       "ASTC_5x4_UNORM_BLOCK",
+	DataFormat_ASTC_5x4_SRGB_BLOCK:                        "ASTC_5x4_SRGB_BLOCK",
+	DataFormat_ASTC_5x5_UNORM_BLOCK:                       "ASTC_5x5_UNORM_BLOCK",
+	DataFormat_ASTC_5x5_SRGB_BLOCK:                        "ASTC_5x5_SRGB_BLOCK",
+	DataFormat_ASTC_6x5_UNORM_BLOCK:                       "ASTC_6x5_UNORM_BLOCK",
+	DataFormat_ASTC_6x5_SRGB_BLOCK:                        "ASTC_6x5_SRGB_BLOCK",
+	DataFormat_ASTC_6x6_UNORM_BLOCK:                       "ASTC_6x6_UNORM_BLOCK",
+	DataFormat_ASTC_6x6_SRGB_BLOCK:                        "ASTC_6x6_SRGB_BLOCK",
+	DataFormat_ASTC_8x5_UNORM_BLOCK:                       "ASTC_8x5_UNORM_BLOCK",
+	DataFormat_ASTC_8x5_SRGB_BLOCK:                        "ASTC_8x5_SRGB_BLOCK",
+	DataFormat_ASTC_8x6_UNORM_BLOCK:                       "ASTC_8x6_UNORM_BLOCK",
+	DataFormat_ASTC_8x6_SRGB_BLOCK:                        "ASTC_8x6_SRGB_BLOCK",
+	DataFormat_ASTC_8x8_UNORM_BLOCK:                       "ASTC_8x8_UNORM_BLOCK",
+	DataFormat_ASTC_8x8_SRGB_BLOCK:                        "ASTC_8x8_SRGB_BLOCK",
+	DataFormat_ASTC_10x5_UNORM_BLOCK:                      "ASTC_10x5_UNORM_BLOCK",
+	DataFormat_ASTC_10x5_SRGB_BLOCK:                       "ASTC_10x5_SRGB_BLOCK",
+	DataFormat_ASTC_10x6_UNORM_BLOCK:                      "ASTC_10x6_UNORM_BLOCK",
+	DataFormat_ASTC_10x6_SRGB_BLOCK:                       "ASTC_10x6_SRGB_BLOCK",
+	DataFormat_ASTC_10x8_UNORM_BLOCK:                      "ASTC_10x8_UNORM_BLOCK",
+	DataFormat_ASTC_10x8_SRGB_BLOCK:                       "ASTC_10x8_SRGB_BLOCK",
+	DataFormat_ASTC_10x10_UNORM_BLOCK:                     "ASTC_10x10_UNORM_BLOCK",
+	DataFormat_ASTC_10x10_SRGB_BLOCK:                      "ASTC_10x10_SRGB_BLOCK",
+	DataFormat_ASTC_12x10_UNORM_BLOCK:                     "ASTC_12x10_UNORM_BLOCK",
+	DataFormat_ASTC_12x10_SRGB_BLOCK:                      "ASTC_12x10_SRGB_BLOCK",
+	DataFormat_ASTC_12x12_UNORM_BLOCK:                     "ASTC_12x12_UNORM_BLOCK",
+	DataFormat_ASTC_12x12_SRGB_BLOCK:                      "ASTC_12x12_SRGB_BLOCK",
+	DataFormat_G8B8G8R8_422_UNORM:                         "G8B8G8R8_422_UNORM",
+	DataFormat_B8G8R8G8_422_UNORM:                         "B8G8R8G8_422_UNORM",
+	DataFormat_G8_B8_R8_3PLANE_420_UNORM:                  "G8_B8_R8_3PLANE_420_UNORM",
+	DataFormat_G8_B8R8_2PLANE_420_UNORM:                   "G8_B8R8_2PLANE_420_UNORM",
+	DataFormat_G8_B8_R8_3PLANE_422_UNORM:                  "G8_B8_R8_3PLANE_422_UNORM",
+	DataFormat_G8_B8R8_2PLANE_422_UNORM:                   "G8_B8R8_2PLANE_422_UNORM",
+	DataFormat_G8_B8_R8_3PLANE_444_UNORM:                  "G8_B8_R8_3PLANE_444_UNORM",
+	DataFormat_R10X6_UNORM_PACK16:                         "R10X6_UNORM_PACK16",
+	DataFormat_R10X6G10X6_UNORM_2PACK16:                   "R10X6G10X6_UNORM_2PACK16",
+	DataFormat_R10X6G10X6B10X6A10X6_UNORM_4PACK16:         "R10X6G10X6B10X6A10X6_UNORM_4PACK16",
+	DataFormat_G10X6B10X6G10X6R10X6_422_UNORM_4PACK16:     "G10X6B10X6G10X6R10X6_422_UNORM_4PACK16",
+	DataFormat_B10X6G10X6R10X6G10X6_422_UNORM_4PACK16:     "B10X6G10X6R10X6G10X6_422_UNORM_4PACK16",
+	DataFormat_G10X6_B10X6_R10X6_3PLANE_420_UNORM_3PACK16: "G10X6_B10X6_R10X6_3PLANE_420_UNORM_3PACK16",
+	DataFormat_G10X6_B10X6R10X6_2PLANE_420_UNORM_3PACK16:  "G10X6_B10X6R10X6_2PLANE_420_UNORM_3PACK16",
+	DataFormat_G10X6_B10X6_R10X6_3PLANE_422_UNORM_3PACK16: "G10X6_B10X6_R10X6_3PLANE_422_UNORM_3PACK16",
+	DataFormat_G10X6_B10X6R10X6_2PLANE_422_UNORM_3PACK16:  "G10X6_B10X6R10X6_2PLANE_422_UNORM_3PACK16",
+	DataFormat_G10X6_B10X6_R10X6_3PLANE_444_UNORM_3PACK16: "G10X6_B10X6_R10X6_3PLANE_444_UNORM_3PACK16",
+	DataFormat_R12X4_UNORM_PACK16:                         "R12X4_UNORM_PACK16",
+	DataFormat_R12X4G12X4_UNORM_2PACK16:                   "R12X4G12X4_UNORM_2PACK16",
+	DataFormat_R12X4G12X4B12X4A12X4_UNORM_4PACK16:         "R12X4G12X4B12X4A12X4_UNORM_4PACK16",
+	DataFormat_G12X4B12X4G12X4R12X4_422_UNORM_4PACK16:     "G12X4B12X4G12X4R12X4_422_UNORM_4PACK16",
+	DataFormat_B12X4G12X4R12X4G12X4_422_UNORM_4PACK16:     "B12X4G12X4R12X4G12X4_422_UNORM_4PACK16",
+	DataFormat_G12X4_B12X4_R12X4_3PLANE_420_UNORM_3PACK16: "G12X4_B12X4_R12X4_3PLANE_420_UNORM_3PACK16",
+	DataFormat_G12X4_B12X4R12X4_2PLANE_420_UNORM_3PACK16:  "G12X4_B12X4R12X4_2PLANE_420_UNORM_3PACK16",
+	DataFormat_G12X4_B12X4_R12X4_3PLANE_422_UNORM_3PACK16: "G12X4_B12X4_R12X4_3PLANE_422_UNORM_3PACK16",
+	DataFormat_G12X4_B12X4R12X4_2PLANE_422_UNORM_3PACK16:  "G12X4_B12X4R12X4_2PLANE_422_UNORM_3PACK16",
+	DataFormat_G12X4_B12X4_R12X4_3PLANE_444_UNORM_3PACK16: "G12X4_B12X4_R12X4_3PLANE_444_UNORM_3PACK16",
+	DataFormat_G16B16G16R16_422_UNORM:                     "G16B16G16R16_422_UNORM",
+	DataFormat_B16G16R16G16_422_UNORM:                     "B16G16R16G16_422_UNORM",
+	DataFormat_G16_B16_R16_3PLANE_420_UNORM:               "G16_B16_R16_3PLANE_420_UNORM",
+	DataFormat_G16_B16R16_2PLANE_420_UNORM:                "G16_B16R16_2PLANE_420_UNORM",
+	DataFormat_G16_B16_R16_3PLANE_422_UNORM:               "G16_B16_R16_3PLANE_422_UNORM",
+	DataFormat_G16_B16R16_2PLANE_422_UNORM:                "G16_B16R16_2PLANE_422_UNORM",
+	DataFormat_G16_B16_R16_3PLANE_444_UNORM:               "G16_B16_R16_3PLANE_444_UNORM",
+}
+
+/*
+FormatClass groups together the [DataFormat]s that can be reinterpreted as one another by a [TextureView]
+(the "compatibility classes" of the Vulkan specification). Uncompressed color formats are compatible when
+they have the same number of bits per texel, compressed formats are compatible when they share the same
+block encoding. Depth, stencil and multi-planar formats are only ever compatible with themselves.
+*/
+type FormatClass int
+
+const (
+	FormatClassExclusive FormatClass = iota // only compatible with the exact same format.
+	FormatClass8Bit
+	FormatClass16Bit
+	FormatClass24Bit
+	FormatClass32Bit
+	FormatClass48Bit
+	FormatClass64Bit
+	FormatClass96Bit
+	FormatClass128Bit
+	FormatClass192Bit
+	FormatClass256Bit
+	FormatClassBC1RGB
+	FormatClassBC1RGBA
+	FormatClassBC2
+	FormatClassBC3
+	FormatClassBC4
+	FormatClassBC5
+	FormatClassBC6H
+	FormatClassBC7
+	FormatClassETC2RGB
+	FormatClassETC2RGBA
+	FormatClassETC2EACRGBA
+	FormatClassEACR
+	FormatClassEACRG
+	FormatClassASTC4x4
+	FormatClassASTC5x4
+	FormatClassASTC5x5
+	FormatClassASTC6x5
+	FormatClassASTC6x6
+	FormatClassASTC8x5
+	FormatClassASTC8x6
+	FormatClassASTC8x8
+	FormatClassASTC10x5
+	FormatClassASTC10x6
+	FormatClassASTC10x8
+	FormatClassASTC10x10
+	FormatClassASTC12x10
+	FormatClassASTC12x12
+)
+
+// Class returns the view compatibility class of the format.
+func (f DataFormat) Class() FormatClass {
+	switch {
+	case f < 0 || f >= DataFormatDefault:
+		return FormatClassExclusive
+	case f == DataFormat_R4G4_UNORM_PACK8:
+		return FormatClass8Bit
+	case f <= DataFormat_A1R5G5B5_UNORM_PACK16:
+		return FormatClass16Bit
+	case f <= DataFormat_R8_SRGB:
+		return FormatClass8Bit
+	case f <= DataFormat_R8G8_SRGB:
+		return FormatClass16Bit
+	case f <= DataFormat_B8G8R8_SRGB:
+		return FormatClass24Bit
+	case f <= DataFormat_A2B10G10R10_SINT_PACK32:
+		return FormatClass32Bit
+	case f <= DataFormat_R16_SFLOAT:
+		return FormatClass16Bit
+	case f <= DataFormat_R16G16_SFLOAT:
+		return FormatClass32Bit
+	case f <= DataFormat_R16G16B16_SFLOAT:
+		return FormatClass48Bit
+	case f <= DataFormat_R16G16B16A16_SFLOAT:
+		return FormatClass64Bit
+	case f <= DataFormat_R32_SFLOAT:
+		return FormatClass32Bit
+	case f <= DataFormat_R32G32_SFLOAT:
+		return FormatClass64Bit
+	case f <= DataFormat_R32G32B32_SFLOAT:
+		return FormatClass96Bit
+	case f <= DataFormat_R32G32B32A32_SFLOAT:
+		return FormatClass128Bit
+	case f <= DataFormat_R64_SFLOAT:
+		return FormatClass64Bit
+	case f <= DataFormat_R64G64_SFLOAT:
+		return FormatClass128Bit
+	case f <= DataFormat_R64G64B64_SFLOAT:
+		return FormatClass192Bit
+	case f <= DataFormat_R64G64B64A64_SFLOAT:
+		return FormatClass256Bit
+	case f <= DataFormat_E5B9G9R9_UFLOAT_PACK32:
+		return FormatClass32Bit
+	case f <= DataFormat_D32_SFLOAT_S8_UINT:
+		return FormatClassExclusive
+	case f <= DataFormat_ASTC_12x12_SRGB_BLOCK:
+		// compressed formats come in UNORM/SRGB or UNORM/SNORM pairs.
+		return FormatClassBC1RGB + FormatClass(f-DataFormat_BC1_RGB_UNORM_BLOCK)/2
+	case f == DataFormat_R10X6_UNORM_PACK16, f == DataFormat_R12X4_UNORM_PACK16:
+		return FormatClass16Bit
+	case f == DataFormat_R10X6G10X6_UNORM_2PACK16, f == DataFormat_R12X4G12X4_UNORM_2PACK16:
+		return FormatClass32Bit
+	default:
+		return FormatClassExclusive
+	}
+}
+
+// Formats returns each of the [DataFormat]s that belong to the class. Returns nil for [FormatClassExclusive].
+func (c FormatClass) Formats() []DataFormat {
+	if c == FormatClassExclusive {
+		return nil
+	}
+	var formats []DataFormat
+	for f := DataFormat(0); f < DataFormatDefault; f++ {
+		if f.Class() == c {
+			formats = append(formats, f)
+		}
+	}
+	return formats
+}
+
+// CompatibleWith returns true if a texture created with format 'f' can be viewed with format 'g'.
+func (f DataFormat) CompatibleWith(g DataFormat) bool {
+	if f == g {
+		return true
+	}
+	class := f.Class()
+	return class != FormatClassExclusive && class == g.Class()
+}
+
+/*
+Block returns the dimensions in texels, and the size in bytes, of each compressed block of a block-compressed
+format. Uncompressed formats are treated as blocks of a single texel, see [DataFormat.Size]. Returns zeros for
+multi-planar formats.
+*/
+func (f DataFormat) Block() (width, height, size int) {
+	switch class := f.Class(); class {
+	case FormatClassBC1RGB, FormatClassBC1RGBA, FormatClassBC4, FormatClassETC2RGB, FormatClassETC2RGBA, FormatClassEACR:
+		return 4, 4, 8
+	case FormatClassBC2, FormatClassBC3, FormatClassBC5, FormatClassBC6H, FormatClassBC7, FormatClassETC2EACRGBA, FormatClassEACRG:
+		return 4, 4, 16
+	default:
+		if class >= FormatClassASTC4x4 && class <= FormatClassASTC12x12 {
+			block := astcBlocks[class-FormatClassASTC4x4]
+			return block[0], block[1], 16
+		}
+	}
+	if size := f.Size(); size > 0 {
+		return 1, 1, size
+	}
+	return 0, 0, 0
+}
+
+// astcBlocks are the block dimensions of each ASTC format class, in order.
+var astcBlocks = [...][2]int{
+	{4, 4}, {5, 4}, {5, 5}, {6, 5}, {6, 6}, {8, 5}, {8, 6}, {8, 8}, {10, 5}, {10, 6}, {10, 8}, {10, 10}, {12, 10}, {12, 12},
+}
+
+// Size returns the number of bytes in a single texel (or vertex attribute) of the format. Returns zero for
+// block-compressed and multi-planar formats, see [DataFormat.Block].
+func (f DataFormat) Size() int {
+	switch f.Class() {
+	case FormatClass8Bit:
+		return 1
+	case FormatClass16Bit:
+		return 2
+	case FormatClass24Bit:
+		return 3
+	case FormatClass32Bit:
+		return 4
+	case FormatClass48Bit:
+		return 6
+	case FormatClass64Bit:
+		return 8
+	case FormatClass96Bit:
+		return 12
+	case FormatClass128Bit:
+		return 16
+	case FormatClass192Bit:
+		return 24
+	case FormatClass256Bit:
+		return 32
+	}
+	switch f {
+	case DataFormat_S8_UINT:
+		return 1
+	case DataFormat_D16_UNORM:
+		return 2
+	case DataFormat_D16_UNORM_S8_UINT:
+		return 3
+	case DataFormat_X8_D24_UNORM_PACK32, DataFormat_D32_SFLOAT, DataFormat_D24_UNORM_S8_UINT:
+		return 4
+	case DataFormat_D32_SFLOAT_S8_UINT:
+		return 5
+	default:
+		return 0
+	}
+}
+
+var dataFormatsByName = sync.OnceValue(func() map[string]DataFormat {
+	formats := make(map[string]DataFormat, len(dataFormatNames))
+	for f, name := range dataFormatNames {
+		formats[name] = DataFormat(f)
+	}
+	return formats
+})
@@ -0,0 +1,86 @@
+package rd
+
+import "sync"
+
+/*
+DeletionQueue defers [Resource.Free] until the frames that may still be using a resource have finished on the
+GPU. Freeing a resource that is used by a frame in flight is unsafe, so resources that are no longer needed are
+passed to [DeletionQueue.Free], and are freed by [DeletionQueue.Advance] once [Interface.FrameDelay] frames
+have ended.
+
+	queue := rd.NewDeletionQueue(RD)
+	for running {
+		if resized {
+			queue.Free(framebufferTextures...)
+			framebufferTextures = createFramebufferTextures(RD)
+		}
+		draw(RD)
+		queue.Advance()
+	}
+	queue.Flush()
+
+A deletion queue is safe for concurrent use.
+*/
+type DeletionQueue struct {
+	mutex   sync.Mutex
+	frame   int
+	pending [][]Resource // resources queued during each of the frames in flight.
+}
+
+// NewDeletionQueue returns a deletion queue that holds resources for the frame delay of the device.
+func NewDeletionQueue(device Interface) *DeletionQueue {
+	return &DeletionQueue{pending: make([][]Resource, max(device.FrameDelay(), 1))}
+}
+
+// Free queues the resources to be freed once the current frame, along with those in flight, have ended.
+func (q *DeletionQueue) Free(resources ...Resource) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	slot := q.frame % len(q.pending)
+	for _, resource := range resources {
+		if resource != nil {
+			q.pending[slot] = append(q.pending[slot], resource)
+		}
+	}
+}
+
+// Advance marks the end of the current frame, freeing the resources that were queued [Interface.FrameDelay]
+// frames ago. It should be called once per frame, after the frame has been submitted.
+func (q *DeletionQueue) Advance() {
+	q.mutex.Lock()
+	q.frame++
+	slot := q.frame % len(q.pending)
+	expired := q.pending[slot]
+	q.pending[slot] = nil
+	q.mutex.Unlock()
+	for _, resource := range expired {
+		resource.Free()
+	}
+}
+
+// Flush frees every queued resource, in the order that they were queued. It should only be called once the
+// device is idle, such as at shutdown.
+func (q *DeletionQueue) Flush() {
+	q.mutex.Lock()
+	var expired []Resource
+	for i := 1; i <= len(q.pending); i++ {
+		slot := (q.frame + i) % len(q.pending)
+		expired = append(expired, q.pending[slot]...)
+		q.pending[slot] = nil
+	}
+	q.mutex.Unlock()
+	for _, resource := range expired {
+		resource.Free()
+	}
+}
+
+// Len returns the number of resources waiting to be freed.
+func (q *DeletionQueue) Len() int {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	n := 0
+	for _, resources := range q.pending {
+		n += len(resources)
+	}
+	return n
+}
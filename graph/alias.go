@@ -0,0 +1,166 @@
+package graph
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"grow.graphics/rd"
+)
+
+// cull returns the passes that contribute to the output of the graph, along with those that do not. A pass is
+// kept if it is marked with [Pass.Keep], if it writes nothing (as the graph cannot see its effects), if it
+// writes to an imported resource or if it writes to a transient texture that is read by a later kept pass.
+func (g *Graph) cull() (kept, culled []*Pass) {
+	wanted := make([]bool, len(g.resources))
+	live := make([]bool, len(g.passes))
+	for i := len(g.passes) - 1; i >= 0; i-- {
+		pass := g.passes[i]
+		needed, writes := pass.keep, false
+		for _, u := range pass.uses {
+			if _, id := u.resource.handle(); u.write {
+				writes = true
+				needed = needed || !g.resources[id].transient || wanted[id]
+			}
+		}
+		if !needed && writes {
+			continue
+		}
+		live[i] = true
+		for _, u := range pass.uses {
+			if _, id := u.resource.handle(); !u.write {
+				wanted[id] = true
+			}
+		}
+	}
+	for i, pass := range g.passes {
+		if live[i] {
+			kept = append(kept, pass)
+		} else {
+			culled = append(culled, pass)
+		}
+	}
+	return kept, culled
+}
+
+// checkTransients returns an error for each transient texture that is read before it is written.
+func (g *Graph) checkTransients(passes []*Pass) []error {
+	var errs []error
+	written := make([]bool, len(g.resources))
+	for _, pass := range passes {
+		for _, u := range pass.uses {
+			if _, id := u.resource.handle(); !u.write && g.resources[id].transient && !written[id] {
+				errs = append(errs, fmt.Errorf("graph: pass %q reads transient texture %q before it is written", pass.name, g.resources[id].name))
+			}
+		}
+		for _, u := range pass.uses {
+			if _, id := u.resource.handle(); u.write {
+				written[id] = true
+			}
+		}
+	}
+	return errs
+}
+
+// transient texture allocated while a plan executes, shared by one or more transient resources.
+type transient struct {
+	key       rd.TextureKey
+	format    rd.TextureFormat
+	view      rd.TextureView
+	resources []int // ids of the transient resources that share the texture.
+	last      int   // last step that uses the texture.
+}
+
+// alias assigns each transient resource used by the passes to a transient texture, sharing textures between
+// resources with the same format and view whose lifetimes don't overlap. Returns the index used to track the
+// hazards of each resource.
+func (p *Plan) alias(passes []*Pass) []int {
+	g := p.graph
+	first := make(map[int]int)
+	last := make(map[int]int)
+	for step, pass := range passes {
+		for _, u := range pass.uses {
+			_, id := u.resource.handle()
+			if !g.resources[id].transient {
+				continue
+			}
+			if _, ok := first[id]; !ok {
+				first[id] = step
+			}
+			last[id] = step
+		}
+	}
+	ids := make([]int, 0, len(first))
+	for id := range first {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if first[ids[i]] != first[ids[j]] {
+			return first[ids[i]] < first[ids[j]]
+		}
+		return ids[i] < ids[j]
+	})
+	physical := make([]int, len(g.resources))
+	for id := range physical {
+		physical[id] = id
+	}
+	for _, id := range ids {
+		r := g.resources[id]
+		key := r.format.Key(r.view)
+		slot := -1
+		for i, t := range p.textures {
+			if t.key == key && t.last < first[id] {
+				slot = i
+				break
+			}
+		}
+		if slot < 0 {
+			p.textures = append(p.textures, &transient{key: key, format: r.format, view: r.view})
+			slot = len(p.textures) - 1
+		}
+		t := p.textures[slot]
+		t.resources = append(t.resources, id)
+		t.last = last[id]
+		physical[id] = len(g.resources) + slot
+	}
+	return physical
+}
+
+// Report on the transient textures of a plan, from its last execution.
+type Report struct {
+	Culled     int // passes culled from the graph.
+	Transients int // transient textures used by the plan.
+	Textures   int // textures allocated for the transient textures.
+	Allocated  int // bytes of texture memory allocated, as measured by [rd.Interface.MemoryUsage].
+	Saved      int // bytes of texture memory that would also have been allocated without aliasing.
+}
+
+func (r Report) String() string {
+	return fmt.Sprintf("%d passes culled, %d transient textures aliased onto %d textures, %d bytes allocated, %d bytes saved",
+		r.Culled, r.Transients, r.Textures, r.Allocated, r.Saved)
+}
+
+// Report returns the report from the last execution of the plan.
+func (p *Plan) Report() Report { return p.report }
+
+// allocate the transient textures of the plan, measuring the memory used by each of them.
+func (p *Plan) allocate(device rd.Interface, resources *Resources) []rd.Texture {
+	p.report = Report{Culled: len(p.Culled), Textures: len(p.textures)}
+	textures := make([]rd.Texture, len(p.textures))
+	for i, t := range p.textures {
+		before := device.MemoryUsage(rd.MemoryTextures)
+		texture := device.Texture(t.format, t.view, nil)
+		size := device.MemoryUsage(rd.MemoryTextures) - before
+		names := make([]string, len(t.resources))
+		for j, id := range t.resources {
+			names[j] = p.graph.resources[id].name
+			resources.transient[id] = texture
+		}
+		texture.SetResourceName(strings.Join(names, "+"))
+		textures[i] = texture
+		p.report.Transients += len(t.resources)
+		p.report.Allocated += size
+		p.report.Saved += size * (len(t.resources) - 1)
+	}
+	return textures
+}
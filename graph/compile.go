@@ -0,0 +1,219 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"grow.graphics/rd"
+)
+
+// stages that can be synchronized with a barrier, in the order that barriers are emitted.
+var stages = [...]rd.Barrier{rd.BarrierVertex, rd.BarrierCompute, rd.BarrierTransfer, rd.BarrierFragment}
+
+// Barrier between passes, see [rd.Interface.Barrier].
+type Barrier struct {
+	From rd.Barrier // stages that must complete.
+	Upto rd.Barrier // stages that must wait.
+}
+
+// Step of a [Plan], barriers are placed before the pass.
+type Step struct {
+	Barriers []Barrier
+	Pass     *Pass
+}
+
+// Plan is a compiled [Graph], ready to be executed.
+type Plan struct {
+	graph    *Graph
+	textures []*transient
+	report   Report
+
+	Steps  []Step
+	Culled []*Pass // passes that were culled, as nothing reads what they write.
+}
+
+// stage returns the pipeline stages in which a pass of the given kind accesses a resource.
+func stage(kind Kind, access Access) rd.Barrier {
+	switch kind {
+	case KindCompute:
+		return rd.BarrierCompute
+	case KindTransfer:
+		return rd.BarrierTransfer
+	default:
+		if access == Attachment {
+			return rd.BarrierFragment
+		}
+		return rd.BarrierRaster
+	}
+}
+
+// check returns an error if the use is not valid for the pass.
+func (p *Pass) check(u use) error {
+	g, id := u.resource.handle()
+	if g != p.graph || id < 0 || id >= len(g.resources) {
+		return fmt.Errorf("graph: pass %q uses a resource from a different graph", p.name)
+	}
+	name := g.resources[id].name
+	switch {
+	case u.access == Attachment && p.kind != KindDrawing:
+		return fmt.Errorf("graph: %v pass %q cannot use %q as an attachment", p.kind, p.name, name)
+	case u.access == Attachment && g.resources[id].buffer != nil:
+		return fmt.Errorf("graph: pass %q cannot use buffer %q as an attachment", p.name, name)
+	case u.access == Copy && p.kind != KindTransfer:
+		return fmt.Errorf("graph: %v pass %q cannot copy %q, use a transfer pass", p.kind, p.name, name)
+	case u.access != Copy && p.kind == KindTransfer:
+		return fmt.Errorf("graph: transfer pass %q must use %q with Copy access", p.name, name)
+	case u.access == Sampled && u.write:
+		return fmt.Errorf("graph: pass %q cannot write to %q with Sampled access", p.name, name)
+	}
+	return nil
+}
+
+// hazards tracks the synchronization state of a resource.
+type hazards struct {
+	writer  int        // step of the last write, or -1.
+	written rd.Barrier // stages of the last write.
+	reader  int        // last step that read since the last write.
+	readers rd.Barrier // stages that read since the last write.
+}
+
+// Compile the graph into a plan, returning an error for each invalid use of a resource.
+func (g *Graph) Compile() (*Plan, error) {
+	var errs []error
+	for _, pass := range g.passes {
+		for _, u := range pass.uses {
+			if err := pass.check(u); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	plan := &Plan{graph: g}
+	passes, culled := g.cull()
+	plan.Culled = culled
+	if errs := g.checkTransients(passes); len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	physical := plan.alias(passes)
+	state := make([]hazards, len(g.resources)+len(plan.textures))
+	for i := range state {
+		state[i] = hazards{writer: -1, reader: -1}
+	}
+	for _, pass := range passes {
+		step := len(plan.Steps)
+		needs := make(map[rd.Barrier]rd.Barrier) // destination stage to source stages.
+		need := func(from, upto rd.Barrier, after int) {
+			for _, dst := range stages {
+				if upto&dst == 0 || plan.covered(from, dst, after) {
+					continue
+				}
+				needs[dst] |= from
+			}
+		}
+		for _, u := range pass.uses {
+			_, id := u.resource.handle()
+			s, at := state[physical[id]], stage(pass.kind, u.access)
+			if s.writer >= 0 {
+				need(s.written, at, s.writer) // read after write, or write after write.
+			}
+			if u.write && s.readers != 0 {
+				need(s.readers, at, s.reader) // write after read.
+			}
+		}
+		for _, u := range pass.uses {
+			_, id := u.resource.handle()
+			s, at := &state[physical[id]], stage(pass.kind, u.access)
+			if u.write {
+				if s.writer != step {
+					s.writer, s.written = step, 0
+				}
+				s.written |= at
+				s.reader, s.readers = -1, 0
+			}
+		}
+		for _, u := range pass.uses {
+			_, id := u.resource.handle()
+			if s := &state[physical[id]]; !u.write && s.writer != step {
+				s.reader = step
+				s.readers |= stage(pass.kind, u.access)
+			}
+		}
+		plan.Steps = append(plan.Steps, Step{Barriers: group(needs), Pass: pass})
+	}
+	return plan, nil
+}
+
+// covered reports whether a barrier placed after the given step already synchronizes from with upto.
+func (p *Plan) covered(from, upto rd.Barrier, after int) bool {
+	for step := after + 1; step < len(p.Steps); step++ {
+		for _, b := range p.Steps[step].Barriers {
+			if b.From&from == from && b.Upto&upto == upto {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// group destination stages that wait on the same source stages into a single barrier.
+func group(needs map[rd.Barrier]rd.Barrier) []Barrier {
+	var barriers []Barrier
+	for _, dst := range stages {
+		from, ok := needs[dst]
+		if !ok {
+			continue
+		}
+		merged := false
+		for i := range barriers {
+			if barriers[i].From == from {
+				barriers[i].Upto |= dst
+				merged = true
+			}
+		}
+		if !merged {
+			barriers = append(barriers, Barrier{From: from, Upto: dst})
+		}
+	}
+	sort.SliceStable(barriers, func(i, j int) bool { return barriers[i].Upto < barriers[j].Upto })
+	return barriers
+}
+
+// Execute the plan on the device, returning any errors from transfer passes. Transient textures are allocated
+// before the first pass and freed after the last pass, see [Plan.Report] for the memory that they used.
+func (p *Plan) Execute(device rd.Interface) error {
+	resources := &Resources{graph: p.graph, transient: make(map[int]rd.Texture)}
+	for _, texture := range p.allocate(device, resources) {
+		defer texture.Free()
+	}
+	var errs []error
+	for _, step := range p.Steps {
+		for _, b := range step.Barriers {
+			device.Barrier(b.From, b.Upto)
+		}
+		pass := step.Pass
+		switch pass.kind {
+		case KindDrawing:
+			device.Drawing(pass.frame(resources), func(drawing rd.Drawing) {
+				draw := func() { pass.draw(resources, drawing) }
+				scopes := pass.scope.path()
+				for i := len(scopes) - 1; i >= 0; i-- {
+					block, s := draw, scopes[i]
+					draw = func() { drawing.DebugBlock(s.name, s.color, block) }
+				}
+				draw()
+			})
+		case KindCompute:
+			device.Compute(func(compute rd.Compute) {
+				pass.compute(resources, compute)
+			})
+		case KindTransfer:
+			if err := pass.transfer(resources, device); err != nil {
+				errs = append(errs, fmt.Errorf("graph: pass %q: %w", pass.name, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,240 @@
+package graph
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"grow.graphics/rd"
+	"grow.graphics/uc"
+)
+
+// stageNames returns the names of the stages in the barrier mask, such as "compute|fragment".
+func stageNames(mask rd.Barrier) string {
+	var names []string
+	for _, stage := range stages {
+		if mask&stage == 0 {
+			continue
+		}
+		switch stage {
+		case rd.BarrierVertex:
+			names = append(names, "vertex")
+		case rd.BarrierCompute:
+			names = append(names, "compute")
+		case rd.BarrierTransfer:
+			names = append(names, "transfer")
+		case rd.BarrierFragment:
+			names = append(names, "fragment")
+		}
+	}
+	return strings.Join(names, "|")
+}
+
+// exportedPlan is the JSON representation of a plan.
+type exportedPlan struct {
+	Passes    []exportedPass     `json:"passes"`
+	Resources []exportedResource `json:"resources"`
+	Scopes    []exportedScope    `json:"scopes,omitempty"`
+}
+
+type exportedPass struct {
+	Name     string            `json:"name"`
+	Kind     string            `json:"kind"`
+	Scope    *int              `json:"scope,omitempty"` // index of the innermost scope.
+	Culled   bool              `json:"culled,omitempty"`
+	Step     *int              `json:"step,omitempty"` // index within the steps of the plan, unless culled.
+	Barriers []exportedBarrier `json:"barriers,omitempty"`
+	Reads    []exportedUse     `json:"reads,omitempty"`
+	Writes   []exportedUse     `json:"writes,omitempty"`
+}
+
+type exportedBarrier struct {
+	From string `json:"from"`
+	Upto string `json:"upto"`
+}
+
+type exportedUse struct {
+	Resource int    `json:"resource"`
+	Access   string `json:"access"`
+}
+
+type exportedResource struct {
+	Name      string `json:"name"`
+	Kind      string `json:"kind"` // texture or buffer.
+	Transient bool   `json:"transient,omitempty"`
+	Texture   *int   `json:"texture,omitempty"` // transient texture that the resource is aliased onto.
+	Format    string `json:"format,omitempty"`
+	Width     int    `json:"width,omitempty"`
+	Height    int    `json:"height,omitempty"`
+}
+
+type exportedScope struct {
+	Name   string `json:"name"`
+	Color  string `json:"color"`
+	Parent *int   `json:"parent,omitempty"`
+}
+
+// hexColor returns the color as #rrggbb.
+func hexColor(color uc.Color) string {
+	channel := func(c float32) int { return int(min(max(c, 0), 1)*255 + 0.5) }
+	return fmt.Sprintf("#%02x%02x%02x", channel(color[0]), channel(color[1]), channel(color[2]))
+}
+
+// export the structure of the plan, including the passes that were culled.
+func (p *Plan) export() exportedPlan {
+	g := p.graph
+	steps := make(map[*Pass]int, len(p.Steps))
+	for i, step := range p.Steps {
+		steps[step.Pass] = i
+	}
+	aliased := make(map[int]int)
+	for i, t := range p.textures {
+		for _, id := range t.resources {
+			aliased[id] = i
+		}
+	}
+	var out exportedPlan
+	for _, pass := range g.passes {
+		exported := exportedPass{Name: pass.name, Kind: strings.ToLower(pass.kind.String())}
+		if pass.scope != nil {
+			exported.Scope = &pass.scope.id
+		}
+		if step, ok := steps[pass]; ok {
+			exported.Step = &step
+			for _, b := range p.Steps[step].Barriers {
+				exported.Barriers = append(exported.Barriers, exportedBarrier{From: stageNames(b.From), Upto: stageNames(b.Upto)})
+			}
+		} else {
+			exported.Culled = true
+		}
+		for _, u := range pass.uses {
+			_, id := u.resource.handle()
+			use := exportedUse{Resource: id, Access: strings.ToLower(u.access.String())}
+			if u.write {
+				exported.Writes = append(exported.Writes, use)
+			} else {
+				exported.Reads = append(exported.Reads, use)
+			}
+		}
+		out.Passes = append(out.Passes, exported)
+	}
+	for id, r := range g.resources {
+		exported := exportedResource{Name: r.name, Kind: "texture", Transient: r.transient}
+		switch {
+		case r.buffer != nil:
+			exported.Kind = "buffer"
+		case r.transient:
+			if texture, ok := aliased[id]; ok {
+				exported.Texture = &texture
+			}
+			exported.Format = r.format.Format.String()
+			exported.Width, exported.Height = r.format.Width, r.format.Height
+		}
+		out.Resources = append(out.Resources, exported)
+	}
+	for _, s := range g.scopes {
+		exported := exportedScope{Name: s.name, Color: hexColor(s.color)}
+		if s.parent != nil {
+			exported.Parent = &s.parent.id
+		}
+		out.Scopes = append(out.Scopes, exported)
+	}
+	return out
+}
+
+/*
+WriteJSON writes the structure of the plan as JSON: its passes (in declaration order, including those that were
+culled) along with the barriers placed before them and the resources that they read and write, the resources of
+the graph and the scopes that the passes are grouped under. Resources and scopes are referred to by index.
+*/
+func (p *Plan) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "\t")
+	return encoder.Encode(p.export())
+}
+
+/*
+WriteDOT writes the structure of the plan as a Graphviz DOT graph, where passes are boxes labelled with the
+barriers placed before them, resources are ellipses (dashed for transient textures) and edges show reads and
+writes. Scopes are drawn as clusters and culled passes are greyed out.
+
+	dot -Tsvg frame.dot -o frame.svg
+*/
+func (p *Plan) WriteDOT(w io.Writer) error {
+	plan := p.export()
+	b := bufio.NewWriter(w)
+	fmt.Fprintln(b, "digraph frame {")
+	fmt.Fprintln(b, "\trankdir=LR;")
+	fmt.Fprintln(b, "\tnode [fontname=\"monospace\"];")
+	children := make(map[int][]int) // scope index (or -1 for the top level) to child scopes.
+	for i, s := range plan.Scopes {
+		parent := -1
+		if s.Parent != nil {
+			parent = *s.Parent
+		}
+		children[parent] = append(children[parent], i)
+	}
+	passes := make(map[int][]int) // scope index (or -1 for the top level) to passes.
+	for i, pass := range plan.Passes {
+		scope := -1
+		if pass.Scope != nil {
+			scope = *pass.Scope
+		}
+		passes[scope] = append(passes[scope], i)
+	}
+	var cluster func(scope int, indent string)
+	cluster = func(scope int, indent string) {
+		for _, i := range passes[scope] {
+			pass := plan.Passes[i]
+			label := pass.Name + "\\n" + pass.Kind
+			for _, barrier := range pass.Barriers {
+				label += "\\nbarrier " + barrier.From + " -> " + barrier.Upto
+			}
+			style := ""
+			if pass.Culled {
+				style = ", style=dotted, fontcolor=gray, color=gray"
+				label += "\\n(culled)"
+			}
+			fmt.Fprintf(b, "%sp%d [shape=box, label=%s%s];\n", indent, i, quote(label), style)
+		}
+		for _, child := range children[scope] {
+			s := plan.Scopes[child]
+			fmt.Fprintf(b, "%ssubgraph cluster_%d {\n", indent, child)
+			fmt.Fprintf(b, "%s\tlabel=%s;\n", indent, quote(s.Name))
+			fmt.Fprintf(b, "%s\tcolor=%s;\n", indent, quote(s.Color))
+			cluster(child, indent+"\t")
+			fmt.Fprintf(b, "%s}\n", indent)
+		}
+	}
+	cluster(-1, "\t")
+	for i, r := range plan.Resources {
+		label := r.Name
+		style := ""
+		if r.Transient {
+			label += fmt.Sprintf("\\n%s %dx%d", r.Format, r.Width, r.Height)
+			if r.Texture != nil {
+				label += "\\ntexture " + strconv.Itoa(*r.Texture)
+			}
+			style = ", style=dashed"
+		}
+		fmt.Fprintf(b, "\tr%d [shape=ellipse, label=%s%s];\n", i, quote(label), style)
+	}
+	for i, pass := range plan.Passes {
+		for _, use := range pass.Reads {
+			fmt.Fprintf(b, "\tr%d -> p%d [label=%s];\n", use.Resource, i, quote(use.Access))
+		}
+		for _, use := range pass.Writes {
+			fmt.Fprintf(b, "\tp%d -> r%d [label=%s];\n", i, use.Resource, quote(use.Access))
+		}
+	}
+	fmt.Fprintln(b, "}")
+	return b.Flush()
+}
+
+// quote a DOT string, preserving \n line breaks within labels.
+func quote(s string) string {
+	return strings.ReplaceAll(strconv.Quote(s), `\\n`, `\n`)
+}
@@ -0,0 +1,281 @@
+/*
+Package graph provides a render graph, where each pass declares the textures and buffers that it reads and
+writes, so that the graph can be compiled into an ordered set of [rd.Interface.Drawing], [rd.Interface.Compute]
+and transfer calls, with the minimal [rd.Interface.Barrier] masks needed between them inserted automatically.
+
+	g := graph.New()
+	scene := g.ImportTexture("scene", sceneTexture)
+	bloom := g.ImportTexture("bloom", bloomTexture)
+	g.Drawing("scene", func(r *graph.Resources) rd.Frame {
+		return rd.Frame{Buffer: sceneFramebuffer}
+	}, func(r *graph.Resources, drawing rd.Drawing) {
+		drawing.SetRenderer(sceneRenderer)
+		drawing.Submit(false, 1, 3)
+	}).Writes(scene, graph.Attachment)
+	g.Compute("bloom", func(r *graph.Resources, compute rd.Compute) {
+		compute.SetProcessor(bloomProcessor)
+		compute.Submit(groupsX, groupsY, 1)
+	}).Reads(scene, graph.Sampled).Writes(bloom, graph.Storage)
+	plan, err := g.Compile()
+	if err != nil {
+		return err
+	}
+	return plan.Execute(RD)
+
+Passes execute in the order that they are declared in, each read of a resource observes the most recent write
+to it by an earlier pass. The first access to an imported resource is assumed to already be synchronized with
+any work that happened outside of the graph.
+
+Transient textures, created with [Graph.CreateTexture], only exist while the plan executes. Transient textures
+with the same format and view, whose lifetimes don't overlap, share the same texture. Passes that only write to
+transient textures that are never read (by passes that are not culled themselves) are culled, unless marked
+with [Pass.Keep].
+
+Passes added within [Graph.Scope] are grouped under a debug label region, which is shown by third-party tools
+such as RenderDoc, and by the DOT and JSON exports of a plan (see [Plan.WriteDOT] and [Plan.WriteJSON]) that
+are intended for reviewing changes to the structure of a frame.
+*/
+package graph
+
+import (
+	"fmt"
+
+	"grow.graphics/rd"
+	"grow.graphics/uc"
+)
+
+// Graph of passes that make up a frame.
+type Graph struct {
+	passes    []*Pass
+	resources []*resource
+	scopes    []*scope
+	scope     *scope // current scope, for passes being added.
+}
+
+// scope is a debug label region, see [rd.Drawing.DebugBlock].
+type scope struct {
+	id     int
+	name   string
+	color  uc.Color
+	parent *scope
+}
+
+// path returns the scopes from the outermost to the innermost.
+func (s *scope) path() []*scope {
+	if s == nil {
+		return nil
+	}
+	return append(s.parent.path(), s)
+}
+
+/*
+Scope groups the passes added by fn under a debug label region with the given name and color. Scopes may be
+nested. Drawing passes are wrapped with [rd.Drawing.DebugBlock] for each of their scopes when the plan
+executes, there is no equivalent for compute and transfer passes, so their scopes only appear in exports.
+*/
+func (g *Graph) Scope(name string, color uc.Color, fn func()) {
+	outer := g.scope
+	g.scope = &scope{id: len(g.scopes), name: name, color: color, parent: outer}
+	g.scopes = append(g.scopes, g.scope)
+	defer func() { g.scope = outer }()
+	fn()
+}
+
+// New returns an empty graph.
+func New() *Graph { return new(Graph) }
+
+type resource struct {
+	name    string
+	texture rd.Texture
+	buffer  rd.Buffer
+
+	transient bool
+	format    rd.TextureFormat
+	view      rd.TextureView
+}
+
+// Resource is a [Texture] or [Buffer] handle.
+type Resource interface {
+	handle() (*Graph, int)
+}
+
+// Texture handle within a graph.
+type Texture struct {
+	graph *Graph
+	id    int
+}
+
+func (t Texture) handle() (*Graph, int) { return t.graph, t.id }
+
+// Buffer handle within a graph.
+type Buffer struct {
+	graph *Graph
+	id    int
+}
+
+func (b Buffer) handle() (*Graph, int) { return b.graph, b.id }
+
+// ImportTexture adds an existing texture to the graph.
+func (g *Graph) ImportTexture(name string, texture rd.Texture) Texture {
+	g.resources = append(g.resources, &resource{name: name, texture: texture})
+	return Texture{graph: g, id: len(g.resources) - 1}
+}
+
+// ImportBuffer adds an existing buffer to the graph.
+func (g *Graph) ImportBuffer(name string, buffer rd.Buffer) Buffer {
+	g.resources = append(g.resources, &resource{name: name, buffer: buffer})
+	return Buffer{graph: g, id: len(g.resources) - 1}
+}
+
+// CreateTexture adds a transient texture to the graph, which is allocated by [Plan.Execute] and freed
+// once the plan has executed. It must be written by a pass before it can be read.
+func (g *Graph) CreateTexture(name string, format rd.TextureFormat, view rd.TextureView) Texture {
+	g.resources = append(g.resources, &resource{name: name, transient: true, format: format, view: view})
+	return Texture{graph: g, id: len(g.resources) - 1}
+}
+
+// Access describes how a pass uses a resource.
+type Access int
+
+const (
+	Attachment Access = iota // color or depth/stencil attachment of the framebuffer of a drawing pass.
+	Sampled                  // read through a sampler, or as a uniform buffer, by a shader.
+	Storage                  // storage image or storage buffer, read or written by a shader.
+	Copy                     // source or destination of a transfer, such as a copy, clear or resolve.
+)
+
+func (a Access) String() string {
+	switch a {
+	case Attachment:
+		return "Attachment"
+	case Sampled:
+		return "Sampled"
+	case Storage:
+		return "Storage"
+	case Copy:
+		return "Copy"
+	default:
+		return fmt.Sprintf("Access(%d)", int(a))
+	}
+}
+
+// Kind of pass.
+type Kind int
+
+const (
+	KindDrawing  Kind = iota // [rd.Interface.Drawing]
+	KindCompute              // [rd.Interface.Compute]
+	KindTransfer             // copies, clears and resolves.
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindDrawing:
+		return "Drawing"
+	case KindCompute:
+		return "Compute"
+	case KindTransfer:
+		return "Transfer"
+	default:
+		return fmt.Sprintf("Kind(%d)", int(k))
+	}
+}
+
+// use of a resource by a pass.
+type use struct {
+	resource Resource
+	access   Access
+	write    bool
+}
+
+// Pass within a graph, use [Pass.Reads] and [Pass.Writes] to declare the resources that it uses.
+type Pass struct {
+	graph *Graph
+	name  string
+	kind  Kind
+	uses  []use
+	keep  bool
+	scope *scope
+
+	frame    func(*Resources) rd.Frame
+	draw     func(*Resources, rd.Drawing)
+	compute  func(*Resources, rd.Compute)
+	transfer func(*Resources, rd.Interface) error
+}
+
+// Name of the pass.
+func (p *Pass) Name() string { return p.name }
+
+// Kind of the pass.
+func (p *Pass) Kind() Kind { return p.kind }
+
+// Keep the pass, even if nothing reads what it writes, for passes with side effects that the graph
+// cannot see.
+func (p *Pass) Keep() *Pass {
+	p.keep = true
+	return p
+}
+
+// Reads declares that the pass reads the resource with the given access.
+func (p *Pass) Reads(r Resource, access Access) *Pass {
+	p.uses = append(p.uses, use{resource: r, access: access})
+	return p
+}
+
+// Writes declares that the pass writes to the resource with the given access. Attachments that are loaded
+// (rather than cleared or overwritten) at the start of the pass should also be declared with [Pass.Reads].
+func (p *Pass) Writes(r Resource, access Access) *Pass {
+	p.uses = append(p.uses, use{resource: r, access: access, write: true})
+	return p
+}
+
+func (g *Graph) add(pass *Pass) *Pass {
+	pass.graph = g
+	pass.scope = g.scope
+	g.passes = append(g.passes, pass)
+	return pass
+}
+
+// Drawing adds a pass that draws into the frame returned by frame, see [rd.Interface.Drawing].
+func (g *Graph) Drawing(name string, frame func(*Resources) rd.Frame, draw func(*Resources, rd.Drawing)) *Pass {
+	return g.add(&Pass{name: name, kind: KindDrawing, frame: frame, draw: draw})
+}
+
+// Compute adds a compute pass, see [rd.Interface.Compute].
+func (g *Graph) Compute(name string, compute func(*Resources, rd.Compute)) *Pass {
+	return g.add(&Pass{name: name, kind: KindCompute, compute: compute})
+}
+
+/*
+Transfer adds a pass that copies, clears or resolves resources with the given device, such as with
+[rd.Interface.TextureCopy] or [rd.Texture.Clear]. As the graph inserts the barriers between passes,
+transfers should pass [rd.BarrierDisable] as their barrier argument.
+*/
+func (g *Graph) Transfer(name string, transfer func(*Resources, rd.Interface) error) *Pass {
+	return g.add(&Pass{name: name, kind: KindTransfer, transfer: transfer})
+}
+
+// Resources resolves the handles of a graph into the resources of the rendering device, while it executes.
+type Resources struct {
+	graph     *Graph
+	transient map[int]rd.Texture
+}
+
+// Texture returns the texture for the handle.
+func (r *Resources) Texture(t Texture) rd.Texture {
+	if t.graph != r.graph {
+		panic("graph: texture handle belongs to a different graph")
+	}
+	if texture, ok := r.transient[t.id]; ok {
+		return texture
+	}
+	return r.graph.resources[t.id].texture
+}
+
+// Buffer returns the buffer for the handle.
+func (r *Resources) Buffer(b Buffer) rd.Buffer {
+	if b.graph != r.graph {
+		panic("graph: buffer handle belongs to a different graph")
+	}
+	return r.graph.resources[b.id].buffer
+}
@@ -0,0 +1,60 @@
+/*
+Package unwrap is shared by the packages that wrap an [rd.Interface], so that the resources they hand out can be
+unwrapped before they are passed back to the underlying device.
+*/
+package unwrap
+
+import "grow.graphics/rd"
+
+// Wrapper of a value created by an underlying device.
+type Wrapper interface {
+	Unwrap() any
+}
+
+// Value returns the value wrapped by v, or v itself if it is not a [Wrapper].
+func Value[T any](v T) T {
+	if w, ok := any(v).(Wrapper); ok {
+		return w.Unwrap().(T)
+	}
+	return v
+}
+
+// Slice returns a copy of the values with each of them unwrapped.
+func Slice[T any](values []T) []T {
+	unwrapped := make([]T, len(values))
+	for i, v := range values {
+		unwrapped[i] = Value(v)
+	}
+	return unwrapped
+}
+
+// Variables returns a copy of the variables with each of them unwrapped, including the resources referred to
+// by composite variables, such as [rd.SamplerWithTexture].
+func Variables(variables map[int]rd.Variable) map[int]rd.Variable {
+	unwrapped := make(map[int]rd.Variable, len(variables))
+	for binding, variable := range variables {
+		switch v := variable.(type) {
+		case rd.SamplerWithTexture:
+			v.Sampler, v.Texture = Value(v.Sampler), Value(v.Texture)
+			variable = v
+		case rd.SamplerWithTextureBuffer:
+			v.Sampler, v.TextureBuffer = Value(v.Sampler), Value(v.TextureBuffer)
+			variable = v
+		case rd.InputAttachment:
+			v.Texture = Value(v.Texture)
+			variable = v
+		default:
+			variable = Value(variable)
+		}
+		unwrapped[binding] = variable
+	}
+	return unwrapped
+}
+
+// VariableBuffer is a uniform or storage buffer, where Variable is the underlying buffer and Buffer wraps it.
+type VariableBuffer struct {
+	rd.Variable
+	rd.Buffer
+}
+
+func (b *VariableBuffer) Unwrap() any { return b.Variable }
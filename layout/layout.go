@@ -0,0 +1,441 @@
+/*
+Package layout encodes Go values into the memory layouts that shaders expect for uniform, storage and
+push constant blocks, such that the results can be passed directly to [rd.Interface.UniformBuffer],
+[rd.Interface.StorageBuffer] or written to a [rd.Buffer].
+
+	type Light struct {
+		Position xy.Vector3 // vec3
+		Radius   float32    // float, packed after the vec3
+		Color    uc.Color   // vec4
+		Shadows  [4]xy.Projection
+	}
+	data, err := layout.Std140(Light{...})
+
+Go types map onto GLSL types as follows:
+
+  - bool, int32, uint32 and float32 are GLSL bool, int, uint and float.
+  - int64, uint64 and float64 are GLSL int64_t, uint64_t and double.
+  - [xy.Vector2], [xy.Vector3], [xy.Vector4] and [uc.Color] are float vectors.
+  - [xy.Vector2i], [xy.Vector3i] and [xy.Vector4i] are int vectors.
+  - [xy.Basis] and [xy.Projection] are mat3 and mat4, encoded column by column, in the order of their fields.
+  - Go arrays are GLSL arrays and Go structs are GLSL structs.
+  - A slice may be used as the final field of the top-level struct, or as the top-level value, for a
+    runtime-sized array.
+
+Other Go types, such as int and uint (which have a platform-dependent size), are not supported.
+*/
+package layout
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sync"
+
+	"grow.graphics/uc"
+	"grow.graphics/xy"
+)
+
+// Rules for laying out memory.
+type Rules int
+
+const (
+	RulesStd140 Rules = iota // uniform buffers.
+	RulesStd430              // storage buffers and push constants.
+)
+
+// Std140 encodes v using the std140 layout rules, as used by uniform buffers.
+func Std140(v any) ([]byte, error) { return Encode(RulesStd140, v) }
+
+// Std430 encodes v using the std430 layout rules, as used by storage buffers and push constants.
+func Std430(v any) ([]byte, error) { return Encode(RulesStd430, v) }
+
+// Encode v using the given layout rules.
+func Encode(rules Rules, v any) ([]byte, error) {
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return nil, fmt.Errorf("layout: cannot encode a nil %v", value.Type())
+		}
+		value = value.Elem()
+	}
+	if !value.IsValid() {
+		return nil, errors.New("layout: cannot encode nil")
+	}
+	var runtime reflect.Value // runtime-sized array
+	base := value
+	switch value.Kind() {
+	case reflect.Slice:
+		runtime, base = value, reflect.Value{}
+	case reflect.Struct:
+		if n := value.NumField(); n > 0 && value.Field(n-1).Kind() == reflect.Slice {
+			runtime = value.Field(n - 1)
+		}
+	}
+	var (
+		buf  []byte
+		size int
+	)
+	if base.IsValid() {
+		t, err := Of(rules, base.Type())
+		if err != nil {
+			return nil, err
+		}
+		size = t.Size
+		if runtime.IsValid() {
+			size = t.Fields[len(t.Fields)-1].Offset
+		}
+		buf = make([]byte, size)
+		t.encode(buf, base)
+	}
+	if runtime.IsValid() {
+		elem, err := Of(rules, runtime.Type().Elem())
+		if err != nil {
+			return nil, err
+		}
+		stride := elem.arrayStride(rules)
+		buf = append(buf, make([]byte, stride*runtime.Len())...)
+		for i := 0; i < runtime.Len(); i++ {
+			elem.encode(buf[size+i*stride:], runtime.Index(i))
+		}
+	}
+	return buf, nil
+}
+
+// Kind of [Type].
+type Kind int
+
+const (
+	Scalar Kind = iota
+	Vector
+	Matrix
+	Array
+	Struct
+)
+
+// Type describes the layout of a Go type.
+type Type struct {
+	Go     reflect.Type
+	Kind   Kind
+	Size   int // in bytes, including any trailing padding.
+	Align  int // base alignment, in bytes.
+	Stride int // between array elements, or matrix columns.
+	Length int // number of vector components, matrix columns or array elements.
+	Elem   *Type
+	Fields []Field // of a struct.
+
+	scalar  reflect.Kind
+	runtime bool // struct ends with a runtime-sized array.
+}
+
+// Field of a struct, with its offset.
+type Field struct {
+	Name   string
+	Offset int
+	Type   *Type
+}
+
+type key struct {
+	rules Rules
+	t     reflect.Type
+}
+
+var cache sync.Map // of key to *Type
+
+// vectors recognized by the layout, along with their number of columns and rows.
+var vectors = map[reflect.Type][2]int{
+	reflect.TypeOf(xy.Vector2{}):    {1, 2},
+	reflect.TypeOf(xy.Vector3{}):    {1, 3},
+	reflect.TypeOf(xy.Vector4{}):    {1, 4},
+	reflect.TypeOf(xy.Vector2i{}):   {1, 2},
+	reflect.TypeOf(xy.Vector3i{}):   {1, 3},
+	reflect.TypeOf(xy.Vector4i{}):   {1, 4},
+	reflect.TypeOf(uc.Color{}):      {1, 4},
+	reflect.TypeOf(xy.Basis{}):      {3, 3},
+	reflect.TypeOf(xy.Projection{}): {4, 4},
+}
+
+// Of returns the layout of the given Go type, according to the given rules.
+func Of(rules Rules, t reflect.Type) (*Type, error) {
+	if cached, ok := cache.Load(key{rules, t}); ok {
+		return cached.(*Type), nil
+	}
+	layout, err := of(rules, t)
+	if err != nil {
+		return nil, err
+	}
+	cache.Store(key{rules, t}, layout)
+	return layout, nil
+}
+
+func of(rules Rules, t reflect.Type) (*Type, error) {
+	if shape, ok := vectors[t]; ok {
+		return matrix(rules, t, shape[0], shape[1])
+	}
+	switch t.Kind() {
+	case reflect.Bool, reflect.Int32, reflect.Uint32, reflect.Float32:
+		return &Type{Go: t, Kind: Scalar, Size: 4, Align: 4, scalar: t.Kind()}, nil
+	case reflect.Int64, reflect.Uint64, reflect.Float64:
+		return &Type{Go: t, Kind: Scalar, Size: 8, Align: 8, scalar: t.Kind()}, nil
+	case reflect.Array:
+		elem, err := Of(rules, t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		if elem.runtime {
+			return nil, fmt.Errorf("layout: %v cannot be an array element, as it has a runtime-sized array", t.Elem())
+		}
+		return &Type{
+			Go:     t,
+			Kind:   Array,
+			Size:   t.Len() * elem.arrayStride(rules),
+			Align:  elem.arrayAlign(rules),
+			Stride: elem.arrayStride(rules),
+			Length: t.Len(),
+			Elem:   elem,
+		}, nil
+	case reflect.Struct:
+		layout := &Type{Go: t, Kind: Struct, Align: 1}
+		offset := 0
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.Type.Kind() == reflect.Slice && i == t.NumField()-1 {
+				elem, err := Of(rules, field.Type.Elem())
+				if err != nil {
+					return nil, err
+				}
+				if elem.runtime {
+					return nil, fmt.Errorf("layout: %v cannot be an array element, as it has a runtime-sized array", field.Type.Elem())
+				}
+				offset = roundUp(offset, elem.arrayAlign(rules))
+				layout.Fields = append(layout.Fields, Field{
+					Name:   field.Name,
+					Offset: offset,
+					Type: &Type{
+						Go:     field.Type,
+						Kind:   Array,
+						Align:  elem.arrayAlign(rules),
+						Stride: elem.arrayStride(rules),
+						Elem:   elem,
+					},
+				})
+				layout.Align = max(layout.Align, elem.arrayAlign(rules))
+				layout.runtime = true
+				continue
+			}
+			ft, err := Of(rules, field.Type)
+			if err != nil {
+				return nil, fmt.Errorf("layout: %v.%s: %w", t, field.Name, err)
+			}
+			if ft.runtime {
+				return nil, fmt.Errorf("layout: %v.%s cannot have a runtime-sized array", t, field.Name)
+			}
+			offset = roundUp(offset, ft.Align)
+			layout.Fields = append(layout.Fields, Field{Name: field.Name, Offset: offset, Type: ft})
+			layout.Align = max(layout.Align, ft.Align)
+			offset += ft.Size
+		}
+		if rules == RulesStd140 {
+			layout.Align = roundUp(layout.Align, 16)
+		}
+		layout.Size = roundUp(offset, layout.Align)
+		return layout, nil
+	default:
+		return nil, fmt.Errorf("layout: unsupported type %v", t)
+	}
+}
+
+// matrix returns the layout of a vector (columns == 1) or a column-major matrix.
+func matrix(rules Rules, t reflect.Type, columns, rows int) (*Type, error) {
+	components := scalars(t, nil)
+	if len(components) != columns*rows {
+		return nil, fmt.Errorf("layout: %v has %d components, expected %d", t, len(components), columns*rows)
+	}
+	for _, component := range components[1:] {
+		if component.Kind() != components[0].Kind() {
+			return nil, fmt.Errorf("layout: %v has components of mixed types", t)
+		}
+	}
+	scalar, err := of(rules, components[0])
+	if err != nil {
+		return nil, fmt.Errorf("layout: %v: %w", t, err)
+	}
+	vector := &Type{
+		Go:     t,
+		Kind:   Vector,
+		Size:   rows * scalar.Size,
+		Align:  4 * scalar.Size,
+		Length: rows,
+		Elem:   scalar,
+		scalar: scalar.scalar,
+	}
+	if rows == 2 {
+		vector.Align = 2 * scalar.Size
+	}
+	if columns == 1 {
+		return vector, nil
+	}
+	vector.Go = nil // columns have no Go type of their own.
+	stride := vector.arrayStride(rules)
+	return &Type{
+		Go:     t,
+		Kind:   Matrix,
+		Size:   columns * stride,
+		Align:  vector.arrayAlign(rules),
+		Stride: stride,
+		Length: columns,
+		Elem:   vector,
+		scalar: scalar.scalar,
+	}, nil
+}
+
+// scalars appends the types of each scalar that makes up t, in order.
+func scalars(t reflect.Type, types []reflect.Type) []reflect.Type {
+	switch t.Kind() {
+	case reflect.Array:
+		for i := 0; i < t.Len(); i++ {
+			types = scalars(t.Elem(), types)
+		}
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			types = scalars(t.Field(i).Type, types)
+		}
+	default:
+		types = append(types, t)
+	}
+	return types
+}
+
+// leaves appends each scalar that makes up v, in order.
+func leaves(v reflect.Value, values []reflect.Value) []reflect.Value {
+	switch v.Kind() {
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			values = leaves(v.Index(i), values)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			values = leaves(v.Field(i), values)
+		}
+	default:
+		values = append(values, v)
+	}
+	return values
+}
+
+// arrayAlign returns the alignment of an array with elements of this type.
+func (t *Type) arrayAlign(rules Rules) int {
+	if rules == RulesStd140 {
+		return roundUp(t.Align, 16)
+	}
+	return t.Align
+}
+
+// arrayStride returns the stride of an array with elements of this type.
+func (t *Type) arrayStride(rules Rules) int {
+	return roundUp(t.Size, t.arrayAlign(rules))
+}
+
+func roundUp(n, align int) int {
+	return (n + align - 1) / align * align
+}
+
+// encode v into buf, which must be at least t.Size bytes long.
+func (t *Type) encode(buf []byte, v reflect.Value) {
+	switch t.Kind {
+	case Scalar:
+		putScalar(buf, t.scalar, v)
+	case Vector:
+		for i, leaf := range leaves(v, nil) {
+			putScalar(buf[i*t.Elem.Size:], t.scalar, leaf)
+		}
+	case Matrix:
+		rows := t.Elem.Length
+		size := t.Elem.Elem.Size
+		for i, leaf := range leaves(v, nil) {
+			putScalar(buf[(i/rows)*t.Stride+(i%rows)*size:], t.scalar, leaf)
+		}
+	case Array:
+		for i := 0; i < v.Len(); i++ {
+			t.Elem.encode(buf[i*t.Stride:], v.Index(i))
+		}
+	case Struct:
+		for i, field := range t.Fields {
+			if field.Type.Kind == Array && field.Type.Length == 0 && field.Type.Go.Kind() == reflect.Slice {
+				continue // runtime-sized arrays are encoded by [Encode].
+			}
+			field.Type.encode(buf[field.Offset:], v.Field(i))
+		}
+	}
+}
+
+// decode buf, which must be at least t.Size bytes long, into the settable v.
+func (t *Type) decode(buf []byte, v reflect.Value) {
+	switch t.Kind {
+	case Scalar:
+		getScalar(buf, t.scalar, v)
+	case Vector:
+		for i, leaf := range leaves(v, nil) {
+			getScalar(buf[i*t.Elem.Size:], t.scalar, leaf)
+		}
+	case Matrix:
+		rows := t.Elem.Length
+		size := t.Elem.Elem.Size
+		for i, leaf := range leaves(v, nil) {
+			getScalar(buf[(i/rows)*t.Stride+(i%rows)*size:], t.scalar, leaf)
+		}
+	case Array:
+		for i := 0; i < v.Len(); i++ {
+			t.Elem.decode(buf[i*t.Stride:], v.Index(i))
+		}
+	case Struct:
+		for i, field := range t.Fields {
+			if field.Type.Kind == Array && field.Type.Length == 0 && field.Type.Go.Kind() == reflect.Slice {
+				continue
+			}
+			field.Type.decode(buf[field.Offset:], v.Field(i))
+		}
+	}
+}
+
+func getScalar(buf []byte, kind reflect.Kind, v reflect.Value) {
+	switch kind {
+	case reflect.Bool:
+		v.SetBool(binary.LittleEndian.Uint32(buf) != 0)
+	case reflect.Int32:
+		v.SetInt(int64(int32(binary.LittleEndian.Uint32(buf))))
+	case reflect.Uint32:
+		v.SetUint(uint64(binary.LittleEndian.Uint32(buf)))
+	case reflect.Float32:
+		v.SetFloat(float64(math.Float32frombits(binary.LittleEndian.Uint32(buf))))
+	case reflect.Int64:
+		v.SetInt(int64(binary.LittleEndian.Uint64(buf)))
+	case reflect.Uint64:
+		v.SetUint(binary.LittleEndian.Uint64(buf))
+	case reflect.Float64:
+		v.SetFloat(math.Float64frombits(binary.LittleEndian.Uint64(buf)))
+	}
+}
+
+func putScalar(buf []byte, kind reflect.Kind, v reflect.Value) {
+	switch kind {
+	case reflect.Bool:
+		if v.Bool() {
+			binary.LittleEndian.PutUint32(buf, 1)
+		}
+	case reflect.Int32:
+		binary.LittleEndian.PutUint32(buf, uint32(v.Int()))
+	case reflect.Uint32:
+		binary.LittleEndian.PutUint32(buf, uint32(v.Uint()))
+	case reflect.Float32:
+		binary.LittleEndian.PutUint32(buf, math.Float32bits(float32(v.Float())))
+	case reflect.Int64:
+		binary.LittleEndian.PutUint64(buf, uint64(v.Int()))
+	case reflect.Uint64:
+		binary.LittleEndian.PutUint64(buf, v.Uint())
+	case reflect.Float64:
+		binary.LittleEndian.PutUint64(buf, math.Float64bits(v.Float()))
+	}
+}
@@ -0,0 +1,112 @@
+package layout
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"grow.graphics/rd"
+	"grow.graphics/rd/spirv"
+)
+
+/*
+PushConstants encodes values of type T for [rd.Drawing.SetData] and [rd.Compute.SetData], using the
+std430 layout rules of push constant blocks.
+
+	type Push struct {
+		Transform xy.Projection
+		Tint      uc.Color
+	}
+	push, err := layout.NewPushConstants[Push](RD)
+	if err != nil {
+		return err
+	}
+	RD.Drawing(frame, func(drawing rd.Drawing) {
+		drawing.SetRenderer(renderer)
+		push.Set(drawing, Push{...})
+		drawing.Submit(false, 1, 3)
+	})
+
+When built with the 'rddebug' build tag, [PushConstants.Set] checks the layout of T against the push constant
+block of the shader currently bound to the target, whenever the target is provided by the validate package
+and the shader's reflection is known (it is not for binaries compiled in a previous run).
+*/
+type PushConstants[T any] struct {
+	layout *Type
+	data   []byte
+}
+
+// DataSetter is implemented by both [rd.Drawing] and [rd.Compute].
+type DataSetter interface {
+	SetData(data []byte)
+}
+
+// NewPushConstants returns the push constants for T, or an error if T cannot be laid out or if it
+// exceeds the [rd.LimitMaxPushConstantSize] of the device.
+func NewPushConstants[T any](device rd.Interface) (*PushConstants[T], error) {
+	t, err := Of(RulesStd430, reflect.TypeOf([0]T{}).Elem())
+	if err != nil {
+		return nil, err
+	}
+	if t.runtime {
+		return nil, fmt.Errorf("layout: push constants %v cannot have a runtime-sized array", t.Go)
+	}
+	if limit := device.Limit(rd.LimitMaxPushConstantSize); t.Size > limit {
+		return nil, fmt.Errorf("layout: push constants %v are %d bytes, exceeding the device limit of %d bytes", t.Go, t.Size, limit)
+	}
+	return &PushConstants[T]{layout: t, data: make([]byte, t.Size)}, nil
+}
+
+// Layout of T.
+func (p *PushConstants[T]) Layout() *Type { return p.layout }
+
+// Encode v, the returned slice is reused by the next call to Encode or Set.
+func (p *PushConstants[T]) Encode(v T) []byte {
+	clear(p.data)
+	p.layout.encode(p.data, reflect.ValueOf(&v).Elem())
+	return p.data
+}
+
+// Set encodes v and passes it to the SetData method of the target.
+func (p *PushConstants[T]) Set(target DataSetter, v T) {
+	if debug {
+		if reflected, ok := target.(interface {
+			PushConstants() (*spirv.Block, bool)
+		}); ok {
+			if block, known := reflected.PushConstants(); known {
+				if err := p.Check(block); err != nil {
+					panic(err)
+				}
+			}
+		}
+	}
+	target.SetData(p.Encode(v))
+}
+
+// Check returns an error if the layout of T does not match the given push constant block,
+// as reflected from a shader.
+func (p *PushConstants[T]) Check(block *spirv.Block) error {
+	if block == nil {
+		return fmt.Errorf("layout: shader has no push constant block for %v", p.layout.Go)
+	}
+	if p.layout.Size < block.Size {
+		return fmt.Errorf("layout: push constants %v are %d bytes, but the shader's %s block is %d bytes",
+			p.layout.Go, p.layout.Size, block.Name, block.Size)
+	}
+	if p.layout.Kind != Struct || block.Type == nil || block.Type.Kind != spirv.KindStruct {
+		return nil
+	}
+	if len(p.layout.Fields) != len(block.Type.Members) {
+		return fmt.Errorf("layout: push constants %v have %d fields, but the shader's %s block has %d members",
+			p.layout.Go, len(p.layout.Fields), block.Name, len(block.Type.Members))
+	}
+	var errs []error
+	for i, field := range p.layout.Fields {
+		member := block.Type.Members[i]
+		if field.Offset != member.Offset {
+			errs = append(errs, fmt.Errorf("layout: push constant field %v.%s is at offset %d, but %s.%s is at offset %d",
+				p.layout.Go, field.Name, field.Offset, block.Name, member.Name, member.Offset))
+		}
+	}
+	return errors.Join(errs...)
+}
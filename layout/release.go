@@ -0,0 +1,5 @@
+//go:build !rddebug
+
+package layout
+
+const debug = false
@@ -0,0 +1,88 @@
+package layout
+
+import (
+	"fmt"
+	"reflect"
+
+	"grow.graphics/rd"
+)
+
+/*
+Elements returns the layout of an array of T, according to the given rules, for use with
+[rd.NewTypedLayout]. Each element is padded to the array stride of T, so that the view
+matches a GLSL array of the corresponding type.
+
+	type Light struct {
+		Position xy.Vector3
+		Radius   float32
+	}
+	buffer, lights, err := layout.StorageBuffer[Light](RD, 0, 64)
+	if err != nil {
+		return err
+	}
+	if err := lights.Append(Light{...}); err != nil {
+		return err
+	}
+*/
+func Elements[T any](rules Rules) (rd.Layout[T], error) {
+	t, err := Of(rules, reflect.TypeOf([0]T{}).Elem())
+	if err != nil {
+		return nil, err
+	}
+	if t.runtime {
+		return nil, fmt.Errorf("layout: %v cannot be an array element, as it has a runtime-sized array", t.Go)
+	}
+	return elements[T]{t: t, stride: t.arrayStride(rules)}, nil
+}
+
+// elements implements [rd.Layout] for arrays laid out by [Of].
+type elements[T any] struct {
+	t      *Type
+	stride int
+}
+
+func (e elements[T]) Stride() int { return e.stride }
+
+func (e elements[T]) Encode(buf []byte, values []T) error {
+	for i := range values {
+		e.t.encode(buf[i*e.stride:], reflect.ValueOf(&values[i]).Elem())
+	}
+	return nil
+}
+
+func (e elements[T]) Decode(buf []byte, values []T) error {
+	for i := range values {
+		e.t.decode(buf[i*e.stride:], reflect.ValueOf(&values[i]).Elem())
+	}
+	return nil
+}
+
+// UniformBuffer creates a uniform buffer with room for capacity elements, containing the given values,
+// laid out with the std140 rules.
+func UniformBuffer[T any](device rd.Interface, capacity int, values ...T) (rd.UniformBuffer, *rd.Typed[T], error) {
+	layout, err := Elements[T](RulesStd140)
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err := rd.TypedData(layout, capacity, values)
+	if err != nil {
+		return nil, nil, err
+	}
+	buffer := device.UniformBuffer(data)
+	return buffer, rd.NewTypedLayout(buffer, layout, len(values), max(capacity, len(values))), nil
+}
+
+// StorageBuffer creates a storage buffer with room for capacity elements, containing the given values,
+// laid out with the std430 rules.
+func StorageBuffer[T any](device rd.Interface, usage rd.StorageBufferUsage, capacity int, values ...T) (rd.StorageBuffer, *rd.Typed[T], error) {
+	layout, err := Elements[T](RulesStd430)
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err := rd.TypedData(layout, capacity, values)
+	if err != nil {
+		return nil, nil, err
+	}
+	buffer := device.StorageBuffer(usage, data)
+	return buffer, rd.NewTypedLayout(buffer, layout, len(values), max(capacity, len(values))), nil
+}
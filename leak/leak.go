@@ -0,0 +1,206 @@
+/*
+Package leak wraps an [rd.Interface] to track the resources created through it, along with the stack that
+created each of them, so that resources which are never freed can be traced back to the code responsible.
+
+	RD, tracker := leak.Wrap(RD)
+	defer tracker.WriteTo(os.Stderr)
+
+Resources are grouped by the site that created them (the first caller outside of the wrapped device), with the
+size of each resource estimated from the data or format it was created with.
+*/
+package leak
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"grow.graphics/rd"
+)
+
+// stackDepth is the maximum number of frames recorded for each allocation.
+const stackDepth = 32
+
+// Tracker of the resources that have been created through a wrapped device, but not yet freed.
+type Tracker struct {
+	mutex sync.Mutex
+	live  map[*allocation]struct{}
+	next  uint64
+}
+
+/*
+Wrap the device, such that each resource created through it (and through the local devices, shaders and
+SPIR-V sources that it creates) is tracked until it is freed.
+*/
+func Wrap(device rd.Interface) (rd.Interface, *Tracker) {
+	t := &Tracker{live: make(map[*allocation]struct{})}
+	return wrapper{Interface: device, tracker: t}, t
+}
+
+// Allocation of a resource that has not been freed.
+type Allocation struct {
+	Method string    // method that created the resource, such as "rd.Interface.Texture".
+	Name   string    // name of the resource, see [rd.Nameable.SetResourceName].
+	Size   int       // estimated size of the resource in bytes, zero if unknown.
+	Stack  []uintptr // program counters of the calls that created the resource, see [runtime.CallersFrames].
+
+	order uint64
+}
+
+// Site returns the caller that created the resource.
+func (a Allocation) Site() runtime.Frame {
+	frames := a.Frames()
+	if len(frames) == 0 {
+		return runtime.Frame{}
+	}
+	return frames[0]
+}
+
+// Frames returns the stack that created the resource, without the frames of the wrapped device.
+func (a Allocation) Frames() []runtime.Frame {
+	var (
+		stack  []runtime.Frame
+		frames = runtime.CallersFrames(a.Stack)
+	)
+	for {
+		frame, more := frames.Next()
+		if frame.Function != "" && !strings.HasPrefix(frame.Function, pkg) {
+			stack = append(stack, frame)
+		}
+		if !more {
+			return stack
+		}
+	}
+}
+
+// allocation tracked by the resources that wrap it.
+type allocation struct {
+	tracker *Tracker
+	Allocation
+}
+
+var pkg = reflect.TypeOf(Tracker{}).PkgPath() + "."
+
+// track a resource created by method, with the given estimated size.
+func (t *Tracker) track(method string, size int) *allocation {
+	pcs := make([]uintptr, stackDepth)
+	pcs = pcs[:runtime.Callers(3, pcs)]
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.next++
+	a := &allocation{tracker: t, Allocation: Allocation{Method: method, Size: size, Stack: pcs, order: t.next}}
+	t.live[a] = struct{}{}
+	return a
+}
+
+func (a *allocation) free() {
+	a.tracker.mutex.Lock()
+	defer a.tracker.mutex.Unlock()
+	delete(a.tracker.live, a)
+}
+
+func (a *allocation) name(name string) {
+	a.tracker.mutex.Lock()
+	defer a.tracker.mutex.Unlock()
+	a.Name = name
+}
+
+// Live returns the resources that have not been freed, in the order that they were created.
+func (t *Tracker) Live() []Allocation {
+	t.mutex.Lock()
+	live := make([]Allocation, 0, len(t.live))
+	for a := range t.live {
+		live = append(live, a.Allocation)
+	}
+	t.mutex.Unlock()
+	sort.Slice(live, func(i, j int) bool { return live[i].order < live[j].order })
+	return live
+}
+
+// Site that created resources which have not been freed.
+type Site struct {
+	runtime.Frame
+
+	Allocations []Allocation // in the order that they were created.
+	Size        int          // total estimated size of the allocations in bytes.
+}
+
+// Sites returns the sites that created the resources which have not been freed, largest first.
+func (t *Tracker) Sites() []Site {
+	type key struct {
+		function, file string
+		line           int
+	}
+	var (
+		sites []Site
+		index = make(map[key]int)
+	)
+	for _, a := range t.Live() {
+		site := a.Site()
+		i, ok := index[key{site.Function, site.File, site.Line}]
+		if !ok {
+			i = len(sites)
+			index[key{site.Function, site.File, site.Line}] = i
+			sites = append(sites, Site{Frame: site})
+		}
+		sites[i].Allocations = append(sites[i].Allocations, a)
+		sites[i].Size += a.Size
+	}
+	sort.SliceStable(sites, func(i, j int) bool {
+		if sites[i].Size != sites[j].Size {
+			return sites[i].Size > sites[j].Size
+		}
+		return len(sites[i].Allocations) > len(sites[j].Allocations)
+	})
+	return sites
+}
+
+/*
+WriteTo writes a report of the resources that have not been freed to w, grouped by the site that created them,
+along with the stack of the first resource created at each site.
+
+	2 live resources (4194560 bytes) created at 2 sites
+
+	main.loadLevel
+		/src/game/level.go:42: 1 resources (4194304 bytes)
+		rd.Interface.Texture "shadows" (4194304 bytes)
+		created by:
+		main.loadLevel
+			/src/game/level.go:42
+		main.main
+			/src/game/main.go:17
+	...
+*/
+func (t *Tracker) WriteTo(w io.Writer) (int64, error) {
+	var (
+		b     strings.Builder
+		sites = t.Sites()
+		count int
+		size  int
+	)
+	for _, site := range sites {
+		count += len(site.Allocations)
+		size += site.Size
+	}
+	fmt.Fprintf(&b, "%d live resources (%d bytes) created at %d sites\n", count, size, len(sites))
+	for _, site := range sites {
+		fmt.Fprintf(&b, "\n%s\n\t%s:%d: %d resources (%d bytes)\n", site.Function, site.File, site.Line, len(site.Allocations), site.Size)
+		for _, a := range site.Allocations {
+			b.WriteString("\t" + a.Method)
+			if a.Name != "" {
+				fmt.Fprintf(&b, " %q", a.Name)
+			}
+			fmt.Fprintf(&b, " (%d bytes)\n", a.Size)
+		}
+		b.WriteString("\tcreated by:\n")
+		for _, frame := range site.Allocations[0].Frames() {
+			fmt.Fprintf(&b, "\t%s\n\t\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		}
+	}
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
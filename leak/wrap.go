@@ -0,0 +1,306 @@
+package leak
+
+import (
+	"grow.graphics/rd"
+	"grow.graphics/rd/internal/unwrap"
+	"grow.graphics/uc"
+	"grow.graphics/xy"
+)
+
+type wrapper struct {
+	rd.Interface
+	tracker *Tracker
+}
+
+func (w wrapper) CompileBinary(data []byte) rd.Shader {
+	return &shader{Shader: w.Interface.CompileBinary(data), allocation: w.tracker.track("rd.Interface.CompileBinary", 0)}
+}
+
+func (w wrapper) CompileSPIRV(name string, source rd.SPIRV) []byte {
+	return w.Interface.CompileSPIRV(name, unwrap.Value(source))
+}
+
+func (w wrapper) CompileSource(cache bool, source rd.ShaderSource) rd.SPIRV {
+	return &spirvSource{SPIRV: w.Interface.CompileSource(cache, source), tracker: w.tracker}
+}
+
+func (w wrapper) Compute(fn func(rd.Compute)) {
+	w.Interface.Compute(func(c rd.Compute) {
+		fn(compute{c})
+	})
+}
+
+func (w wrapper) Drawing(frame rd.Frame, fn func(rd.Drawing)) {
+	frame.Storage = unwrap.Slice(frame.Storage)
+	w.Interface.Drawing(frame, func(d rd.Drawing) {
+		fn(drawing{d})
+	})
+}
+
+func (w wrapper) DrawingOnScreen(s rd.Screen, clear uc.Color, fn func(rd.Drawing)) {
+	w.Interface.DrawingOnScreen(unwrap.Value(s), clear, func(d rd.Drawing) {
+		fn(drawing{d})
+	})
+}
+
+// ExtensionTexture is tracked with a size of zero, as the image is owned by another API.
+func (w wrapper) ExtensionTexture(ttype rd.TextureType, format rd.DataFormat, samples rd.TextureSamples, usage rd.TextureUsage, image uintptr, width, height, depth, layers int) rd.Texture {
+	t := w.Interface.ExtensionTexture(ttype, format, samples, usage, image, width, height, depth, layers)
+	return &texture{Texture: t, allocation: w.tracker.track("rd.Interface.ExtensionTexture", 0)}
+}
+
+func (w wrapper) FramebufferFormat(eyes int, attachments []rd.AttachmentFormat, passes []rd.FramebufferPass) rd.FramebufferFormat {
+	return framebufferFormat{w.Interface.FramebufferFormat(eyes, attachments, passes)}
+}
+
+func (w wrapper) IndexBufferU16(data []uint16) rd.IndexBuffer {
+	return &buffer{Buffer: w.Interface.IndexBufferU16(data), allocation: w.tracker.track("rd.Interface.IndexBufferU16", 2*len(data))}
+}
+
+func (w wrapper) IndexBufferU32(data []uint32) rd.IndexBuffer {
+	return &buffer{Buffer: w.Interface.IndexBufferU32(data), allocation: w.tracker.track("rd.Interface.IndexBufferU32", 4*len(data))}
+}
+
+func (w wrapper) Processor(s rd.Shader, defines []any) rd.Processor {
+	return w.Interface.Processor(unwrap.Value(s), defines)
+}
+
+func (w wrapper) Renderer(s rd.Shader, options rd.RenderingOptions) rd.Renderer {
+	options.FramebufferFormat = unwrap.Value(options.FramebufferFormat)
+	return &renderer{Renderer: w.Interface.Renderer(unwrap.Value(s), options), allocation: w.tracker.track("rd.Interface.Renderer", 0)}
+}
+
+func (w wrapper) RenderingDevice() rd.Local {
+	device := w.Interface.RenderingDevice()
+	return local{wrapper: wrapper{Interface: device, tracker: w.tracker}, local: device}
+}
+
+func (w wrapper) Sampler(state rd.SamplerState) rd.Sampler {
+	return &sampler{Sampler: w.Interface.Sampler(state), allocation: w.tracker.track("rd.Interface.Sampler", 0)}
+}
+
+func (w wrapper) Screen(n int) rd.Screen {
+	return screen{w.Interface.Screen(n)}
+}
+
+func (w wrapper) Shader() rd.Shader {
+	return &shader{Shader: w.Interface.Shader(), allocation: w.tracker.track("rd.Interface.Shader", 0)}
+}
+
+// SharedTexture is tracked with a size of zero, as it shares the memory of the texture it was created with.
+func (w wrapper) SharedTexture(view rd.TextureView, with rd.Texture) rd.Texture {
+	t := w.Interface.SharedTexture(view, unwrap.Value(with))
+	return &texture{Texture: t, allocation: w.tracker.track("rd.Interface.SharedTexture", 0)}
+}
+
+func (w wrapper) StorageBuffer(usage rd.StorageBufferUsage, data []byte) rd.StorageBuffer {
+	b := w.Interface.StorageBuffer(usage, data)
+	return &unwrap.VariableBuffer{Variable: b, Buffer: &buffer{Buffer: b, allocation: w.tracker.track("rd.Interface.StorageBuffer", len(data))}}
+}
+
+func (w wrapper) Texture(format rd.TextureFormat, view rd.TextureView, data [][]byte) rd.Texture {
+	t := w.Interface.Texture(format, view, data)
+	return &texture{Texture: t, allocation: w.tracker.track("rd.Interface.Texture", format.Size())}
+}
+
+func (w wrapper) TextureBuffer(format rd.DataFormat, data []byte) rd.TextureBuffer {
+	b := w.Interface.TextureBuffer(format, data)
+	return &textureBuffer{TextureBuffer: b, allocation: w.tracker.track("rd.Interface.TextureBuffer", len(data))}
+}
+
+func (w wrapper) TextureCopy(src, dst rd.Texture, from, into, size xy.Vector3, src_mipmap, dst_mipmap, src_layer, dst_layer int, barrier rd.Barrier) error {
+	return w.Interface.TextureCopy(unwrap.Value(src), unwrap.Value(dst), from, into, size, src_mipmap, dst_mipmap, src_layer, dst_layer, barrier)
+}
+
+func (w wrapper) TextureResolveMultiSample(from, into rd.Texture, barrier rd.Barrier) error {
+	return w.Interface.TextureResolveMultiSample(unwrap.Value(from), unwrap.Value(into), barrier)
+}
+
+func (w wrapper) UniformBuffer(data []byte) rd.UniformBuffer {
+	b := w.Interface.UniformBuffer(data)
+	return &unwrap.VariableBuffer{Variable: b, Buffer: &buffer{Buffer: b, allocation: w.tracker.track("rd.Interface.UniformBuffer", len(data))}}
+}
+
+func (w wrapper) VertexArray(vertices int, format rd.VertexFormat, buffers []rd.Buffer, offsets []int64) rd.VertexArray {
+	return w.Interface.VertexArray(vertices, format, unwrap.Slice(buffers), offsets)
+}
+
+func (w wrapper) VertexBuffer(data []byte) rd.VertexBuffer {
+	return &buffer{Buffer: w.Interface.VertexBuffer(data), allocation: w.tracker.track("rd.Interface.VertexBuffer", len(data))}
+}
+
+type local struct {
+	wrapper
+	local rd.Local
+}
+
+func (l local) Submit() { l.local.Submit() }
+func (l local) Sync()   { l.local.Sync() }
+
+// drawing unwraps the resources bound to it.
+type drawing struct {
+	rd.Drawing
+}
+
+func (d drawing) SetRenderer(r rd.Renderer) { d.Drawing.SetRenderer(unwrap.Value(r)) }
+
+func (d drawing) SetVariables(level rd.VariableLevel, variables rd.Variables) {
+	d.Drawing.SetVariables(level, unwrap.Value(variables))
+}
+
+// compute unwraps the resources bound to it.
+type compute struct {
+	rd.Compute
+}
+
+func (c compute) SetVariables(level rd.VariableLevel, variables rd.Variables) {
+	c.Compute.SetVariables(level, unwrap.Value(variables))
+}
+
+// framebufferFormat unwraps the textures of the framebuffers created with it.
+type framebufferFormat struct {
+	rd.FramebufferFormat
+}
+
+func (f framebufferFormat) Unwrap() any { return f.FramebufferFormat }
+
+func (f framebufferFormat) Framebuffer(textures []rd.Texture) rd.Framebuffer {
+	return f.FramebufferFormat.Framebuffer(unwrap.Slice(textures))
+}
+
+// screen wraps its framebuffer format.
+type screen struct {
+	rd.Screen
+}
+
+func (s screen) Unwrap() any { return s.Screen }
+
+func (s screen) FramebufferFormat() rd.FramebufferFormat {
+	return framebufferFormat{s.Screen.FramebufferFormat()}
+}
+
+// spirvSource tracks the shaders created from compiled source.
+type spirvSource struct {
+	rd.SPIRV
+	tracker *Tracker
+}
+
+func (s *spirvSource) Unwrap() any { return s.SPIRV }
+
+func (s *spirvSource) Shader(name string) rd.Shader {
+	return &shader{Shader: s.SPIRV.Shader(name), allocation: s.tracker.track("rd.SPIRV.Shader", 0)}
+}
+
+type texture struct {
+	rd.Texture
+	*allocation
+}
+
+func (t *texture) Unwrap() any { return t.Texture }
+
+func (t *texture) Free() {
+	t.free()
+	t.Texture.Free()
+}
+
+func (t *texture) SetResourceName(name string) {
+	t.name(name)
+	t.Texture.SetResourceName(name)
+}
+
+type buffer struct {
+	rd.Buffer
+	*allocation
+}
+
+func (b *buffer) Unwrap() any { return b.Buffer }
+
+func (b *buffer) Free() {
+	b.free()
+	b.Buffer.Free()
+}
+
+func (b *buffer) SetResourceName(name string) {
+	b.name(name)
+	b.Buffer.SetResourceName(name)
+}
+
+type textureBuffer struct {
+	rd.TextureBuffer
+	*allocation
+}
+
+func (b *textureBuffer) Unwrap() any { return b.TextureBuffer }
+
+func (b *textureBuffer) Free() {
+	b.free()
+	b.TextureBuffer.Free()
+}
+
+func (b *textureBuffer) SetResourceName(name string) {
+	b.name(name)
+	b.TextureBuffer.SetResourceName(name)
+}
+
+type sampler struct {
+	rd.Sampler
+	*allocation
+}
+
+func (s *sampler) Unwrap() any { return s.Sampler }
+
+func (s *sampler) Free() {
+	s.free()
+	s.Sampler.Free()
+}
+
+func (s *sampler) SetResourceName(name string) {
+	s.name(name)
+	s.Sampler.SetResourceName(name)
+}
+
+type shader struct {
+	rd.Shader
+	*allocation
+}
+
+func (s *shader) Unwrap() any { return s.Shader }
+
+func (s *shader) Variables(variables map[int]rd.Variable) rd.Variables {
+	v := s.Shader.Variables(unwrap.Variables(variables))
+	return &shaderVariables{Variables: v, allocation: s.tracker.track("rd.Shader.Variables", 0)}
+}
+
+func (s *shader) Free() {
+	s.free()
+	s.Shader.Free()
+}
+
+func (s *shader) SetResourceName(name string) {
+	s.name(name)
+	s.Shader.SetResourceName(name)
+}
+
+type shaderVariables struct {
+	rd.Variables
+	*allocation
+}
+
+func (v *shaderVariables) Unwrap() any { return v.Variables }
+
+func (v *shaderVariables) Free() {
+	v.free()
+	v.Variables.Free()
+}
+
+type renderer struct {
+	rd.Renderer
+	*allocation
+}
+
+func (r *renderer) Unwrap() any { return r.Renderer }
+
+func (r *renderer) Free() {
+	r.free()
+	r.Renderer.Free()
+}
@@ -0,0 +1,150 @@
+package rd
+
+import (
+	"fmt"
+	"sync"
+)
+
+/*
+TexturePool recycles textures with the same [TextureFormat] and [TextureView], such as the render targets of a
+post-processing chain, instead of creating and freeing identical textures every frame.
+
+	pool := rd.NewTexturePool(RD, 60)
+	for running {
+		bright := pool.Texture(half, rd.TextureView{})
+		blurred := pool.Texture(half, rd.TextureView{})
+		bloom(bright, blurred)
+		pool.Advance()
+	}
+	pool.Free()
+
+Textures handed out by [TexturePool.Texture] belong to the caller until the end of the frame, when
+[TexturePool.Advance] returns them to the pool, such that their contents must not be relied upon in later
+frames. Textures are recycled for one another when their formats and views have the same [TextureKey]. A
+texture that has not been handed out for the given number of frames is freed.
+
+A texture pool is safe for concurrent use.
+*/
+type TexturePool struct {
+	device Interface
+	evict  int
+
+	mutex     sync.Mutex
+	frame     int
+	available map[TextureKey][]pooledTexture // most recently used last.
+	used      []pooledTexture                // handed out during the current frame.
+	stats     TexturePoolStats
+}
+
+// TexturePoolStats are counters of a [TexturePool].
+type TexturePoolStats struct {
+	Textures int // textures held by the pool, including those in use.
+	InUse    int // textures handed out during the current frame.
+	Created  int // textures created by the pool.
+	Reused   int // textures handed out that were recycled from an earlier frame.
+	Evicted  int // textures freed after going unused.
+}
+
+func (s TexturePoolStats) String() string {
+	return fmt.Sprintf("%d textures (%d in use), %d created, %d reused, %d evicted", s.Textures, s.InUse, s.Created, s.Reused, s.Evicted)
+}
+
+type pooledTexture struct {
+	key     TextureKey
+	texture Texture
+	frame   int // last frame that the texture was handed out.
+}
+
+// NewTexturePool returns a texture pool that frees textures which have not been handed out for evict frames,
+// or for the [Interface.FrameDelay] of the device, whichever is longer.
+func NewTexturePool(device Interface, evict int) *TexturePool {
+	return &TexturePool{
+		device:    device,
+		evict:     max(evict, device.FrameDelay(), 1),
+		available: make(map[TextureKey][]pooledTexture),
+	}
+}
+
+// Texture returns a texture with the given format and view, recycling one that was handed out during an
+// earlier frame if possible. The texture is returned to the pool at the end of the current frame.
+func (p *TexturePool) Texture(format TextureFormat, view TextureView) Texture {
+	key := format.Key(view)
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	var texture Texture
+	if available := p.available[key]; len(available) > 0 {
+		texture = available[len(available)-1].texture
+		p.available[key] = available[:len(available)-1]
+		p.stats.Reused++
+	} else {
+		texture = p.device.Texture(format, view, nil)
+		p.stats.Created++
+		p.stats.Textures++
+	}
+	p.used = append(p.used, pooledTexture{key: key, texture: texture, frame: p.frame})
+	return texture
+}
+
+// Advance marks the end of the current frame, returning the textures handed out during it to the pool and
+// freeing those that have gone unused for too long. It should be called once per frame.
+func (p *TexturePool) Advance() {
+	p.mutex.Lock()
+	p.frame++
+	for _, t := range p.used {
+		p.available[t.key] = append(p.available[t.key], t)
+	}
+	p.used = p.used[:0]
+	var evicted []Texture
+	for key, available := range p.available {
+		kept := available[:0]
+		for _, t := range available {
+			if p.frame-t.frame > p.evict {
+				evicted = append(evicted, t.texture)
+			} else {
+				kept = append(kept, t)
+			}
+		}
+		if len(kept) == 0 {
+			delete(p.available, key)
+		} else {
+			clear(available[len(kept):])
+			p.available[key] = kept
+		}
+	}
+	p.stats.Evicted += len(evicted)
+	p.stats.Textures -= len(evicted)
+	p.mutex.Unlock()
+	for _, texture := range evicted {
+		texture.Free()
+	}
+}
+
+// Stats returns the counters of the pool.
+func (p *TexturePool) Stats() TexturePoolStats {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	stats := p.stats
+	stats.InUse = len(p.used)
+	return stats
+}
+
+// Free every texture held by the pool, including those in use. The pool remains usable.
+func (p *TexturePool) Free() {
+	p.mutex.Lock()
+	textures := make([]Texture, 0, p.stats.Textures)
+	for _, available := range p.available {
+		for _, t := range available {
+			textures = append(textures, t.texture)
+		}
+	}
+	for _, t := range p.used {
+		textures = append(textures, t.texture)
+	}
+	clear(p.available)
+	p.used = nil
+	p.stats.Textures = 0
+	p.mutex.Unlock()
+	for _, texture := range textures {
+		texture.Free()
+	}
+}
@@ -0,0 +1,293 @@
+/*
+Package preprocess resolves #include directives and injects #define directives into shader source code, ahead
+of [rd.Interface.CompileSource]. #line directives are emitted around each included file, so that any errors
+reported by the shader compiler refer back to the original files.
+
+	pre := preprocess.Preprocessor{
+		FS:      shaders,
+		Defines: map[string]string{"USE_SHADOWS": "1"},
+	}
+	source, dependencies, err := pre.ShaderSource(preprocess.Files{
+		Vertex:   "scene.vert",
+		Fragment: "scene.frag",
+	})
+	if err != nil {
+		return err
+	}
+	spirv := RD.CompileSource(true, source)
+
+Quoted includes are resolved relative to the directory of the including file, whilst angle bracket includes
+are resolved relative to the root of the file system. A file that contains '#pragma once' is included at most
+once per stage. Any #version directive in an included file is dropped, as only the top-level file of each
+stage may declare the version. Includes are resolved regardless of any surrounding #if directives, as these
+are left for the shader compiler to evaluate.
+*/
+package preprocess
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"grow.graphics/rd"
+)
+
+// Preprocessor configuration.
+type Preprocessor struct {
+	FS       fs.FS             // file system to read shader files from.
+	Defines  map[string]string // injected immediately after the #version directive, in sorted order.
+	Language rd.ShaderLanguage // determines the header needed for #line directives.
+}
+
+// Files to read each shader stage from, empty names are skipped.
+type Files struct {
+	Compute               string
+	Fragment              string
+	TesselationControl    string
+	TesselationEvaluation string
+	Vertex                string
+}
+
+/*
+ShaderSource preprocesses each of the files into a [rd.ShaderSource] and returns the sorted list of
+every file that was read, which can be used to decide when the shader needs to be recompiled.
+*/
+func (p *Preprocessor) ShaderSource(files Files) (rd.ShaderSource, []string, error) {
+	source := rd.ShaderSource{Language: p.Language}
+	seen := make(map[string]bool)
+	for _, stage := range []struct {
+		name string
+		code *[]byte
+	}{
+		{files.Compute, &source.Compute},
+		{files.Fragment, &source.Fragment},
+		{files.TesselationControl, &source.TesselationControl},
+		{files.TesselationEvaluation, &source.TesselationEvaluation},
+		{files.Vertex, &source.Vertex},
+	} {
+		if stage.name == "" {
+			continue
+		}
+		code, dependencies, err := p.File(stage.name)
+		if err != nil {
+			return rd.ShaderSource{}, nil, err
+		}
+		*stage.code = code
+		for _, dependency := range dependencies {
+			seen[dependency] = true
+		}
+	}
+	dependencies := make([]string, 0, len(seen))
+	for dependency := range seen {
+		dependencies = append(dependencies, dependency)
+	}
+	sort.Strings(dependencies)
+	return source, dependencies, nil
+}
+
+// File preprocesses the named file, returning the resulting source code, along with each file that
+// was read, in the order that they were first included.
+func (p *Preprocessor) File(name string) ([]byte, []string, error) {
+	if !fs.ValidPath(name) {
+		return nil, nil, fmt.Errorf("preprocess: invalid file name %q", name)
+	}
+	s := state{
+		Preprocessor: p,
+		once:         make(map[string]bool),
+		read:         make(map[string]bool),
+	}
+	if err := s.file(name); err != nil {
+		return nil, nil, err
+	}
+	return s.out.Bytes(), s.dependencies, nil
+}
+
+type state struct {
+	*Preprocessor
+
+	out          bytes.Buffer
+	once         map[string]bool
+	read         map[string]bool
+	stack        []string
+	dependencies []string
+	header       bool // true once the defines have been written.
+}
+
+// writeHeader writes the extensions and defines that are injected after the #version directive.
+func (s *state) writeHeader() {
+	s.header = true
+	if s.Language == rd.ShaderLanguageGLSL {
+		s.out.WriteString("#extension GL_GOOGLE_cpp_style_line_directive : require\n")
+	}
+	names := make([]string, 0, len(s.Defines))
+	for name := range s.Defines {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(&s.out, "#define %s %s\n", name, s.Defines[name])
+	}
+}
+
+func (s *state) line(number int, name string) {
+	fmt.Fprintf(&s.out, "#line %d %s\n", number, strconv.Quote(name))
+}
+
+func (s *state) file(name string) error {
+	if s.once[name] {
+		return nil
+	}
+	for _, including := range s.stack {
+		if including == name {
+			return fmt.Errorf("preprocess: %s includes itself (via %s)", name, strings.Join(s.stack, " -> "))
+		}
+	}
+	data, err := fs.ReadFile(s.FS, name)
+	if err != nil {
+		return err
+	}
+	if !s.read[name] {
+		s.read[name] = true
+		s.dependencies = append(s.dependencies, name)
+	}
+	s.stack = append(s.stack, name)
+	defer func() { s.stack = s.stack[:len(s.stack)-1] }()
+
+	lines := strings.SplitAfter(string(data), "\n")
+	if len(s.stack) == 1 && !hasVersion(lines) {
+		s.writeHeader()
+		s.line(1, name)
+	}
+	comment := false
+	for i, line := range lines {
+		if line == "" {
+			continue
+		}
+		number := i + 1
+		if !comment {
+			directive, argument := parse(line)
+			switch {
+			case directive == "version" && len(s.stack) > 1:
+				s.out.WriteString("\n") // only the top-level file may declare the version.
+				continue
+			case directive == "version" && !s.header:
+				s.out.WriteString(strings.TrimRight(line, "\r\n") + "\n")
+				s.writeHeader()
+				s.line(number+1, name)
+				continue
+			case directive == "pragma" && argument == "once":
+				s.once[name] = true
+				s.out.WriteString("\n")
+				continue
+			case directive == "include":
+				include, err := resolve(name, argument)
+				if err != nil {
+					return fmt.Errorf("%s:%d: %w", name, number, err)
+				}
+				if s.once[include] {
+					s.out.WriteString("\n")
+					continue
+				}
+				s.line(1, include)
+				if err := s.file(include); err != nil {
+					return fmt.Errorf("%s:%d: %w", name, number, err)
+				}
+				s.line(number+1, name)
+				continue
+			}
+		}
+		s.out.WriteString(line)
+		if !strings.HasSuffix(line, "\n") {
+			s.out.WriteString("\n")
+		}
+		comment = inComment(line, comment)
+	}
+	return nil
+}
+
+// hasVersion returns true if any of the lines is a #version directive.
+func hasVersion(lines []string) bool {
+	for _, line := range lines {
+		if directive, _ := parse(line); directive == "version" {
+			return true
+		}
+	}
+	return false
+}
+
+// parse returns the name and argument of a preprocessor directive, or an empty name if the line
+// is not a directive.
+func parse(line string) (directive, argument string) {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "#") {
+		return "", ""
+	}
+	line = strings.TrimSpace(line[1:])
+	directive = line
+	if i := strings.IndexAny(line, " \t"); i >= 0 {
+		directive, argument = line[:i], line[i:]
+	}
+	return directive, strings.TrimSpace(uncomment(argument))
+}
+
+// uncomment removes any trailing // comment from the argument of a directive, ignoring any that
+// appear within a quoted or angle bracketed file name.
+func uncomment(argument string) string {
+	var closing byte
+	for i := 0; i < len(argument); i++ {
+		switch c := argument[i]; {
+		case closing != 0:
+			if c == closing {
+				closing = 0
+			}
+		case c == '"':
+			closing = '"'
+		case c == '<':
+			closing = '>'
+		case c == '/' && i+1 < len(argument) && argument[i+1] == '/':
+			return argument[:i]
+		}
+	}
+	return argument
+}
+
+// resolve the argument of an #include directive within the named file.
+func resolve(name, argument string) (string, error) {
+	if len(argument) < 2 {
+		return "", fmt.Errorf("preprocess: malformed #include %s", argument)
+	}
+	var include string
+	switch first, last := argument[0], argument[len(argument)-1]; {
+	case first == '"' && last == '"':
+		include = path.Join(path.Dir(name), argument[1:len(argument)-1])
+	case first == '<' && last == '>':
+		include = path.Clean(argument[1 : len(argument)-1])
+	default:
+		return "", fmt.Errorf("preprocess: malformed #include %s", argument)
+	}
+	if !fs.ValidPath(include) {
+		return "", fmt.Errorf("preprocess: #include %s is outside of the file system", argument)
+	}
+	return include, nil
+}
+
+// inComment reports whether a block comment is still open at the end of the line.
+func inComment(line string, comment bool) bool {
+	for i := 0; i < len(line)-1; i++ {
+		switch {
+		case comment && line[i] == '*' && line[i+1] == '/':
+			comment = false
+			i++
+		case !comment && line[i] == '/' && line[i+1] == '*':
+			comment = true
+			i++
+		case !comment && line[i] == '/' && line[i+1] == '/':
+			return false
+		}
+	}
+	return comment
+}
@@ -22,7 +22,7 @@ type RenderingOptions struct {
 	ColorBlending ColorBlending
 	DynamicStates DynamicStates
 	RenderingPass int
-	ShaderDefines []any // can be boolean and/or numeric values
+	ShaderDefines []any // can be boolean and/or numeric values, see [Constants].
 }
 
 // PrimitiveType defines how vertices will be interpreted by the renderer.
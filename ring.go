@@ -0,0 +1,165 @@
+package rd
+
+import (
+	"fmt"
+	"sync"
+)
+
+/*
+Ring sub-allocates short-lived data, such as the uniforms of each object drawn in a frame, out of large uniform
+or storage buffers, instead of creating a buffer for every object in every frame.
+
+	ring, err := rd.NewUniformRing(RD, 1<<16)
+	if err != nil {
+		return err
+	}
+	for running {
+		RD.Drawing(frame, func(drawing rd.Drawing) {
+			for _, object := range objects {
+				slice, err := ring.Write(object.Uniforms())
+				if err != nil {
+					log.Println(err)
+					continue
+				}
+				drawing.SetVariables(rd.VariablesForInstance, variables[slice.Buffer])
+				push.Set(drawing, Push{Offset: uint32(slice.Offset)})
+				drawing.Submit(false, 1, object.Vertices)
+			}
+		})
+		ring.Advance()
+	}
+	ring.Free()
+
+Each buffer of the ring is divided into [Interface.FrameDelay] partitions, one for each frame in flight, so that
+the slices handed out during a frame are not reused until that frame has ended. If a frame uses up its partition
+of a buffer, another buffer is created, which is then kept for the following frames. Buffers are bound as a whole,
+so the offset of each slice is passed to the shader, such as through push constants.
+
+A ring is safe for concurrent use.
+*/
+type Ring struct {
+	create    func(size int) Buffer
+	align     int // offset alignment of each slice, in bytes.
+	partition int // size of the partition of each buffer used by a frame, in bytes.
+	frames    int // number of partitions in each buffer.
+
+	mutex   sync.Mutex
+	buffers []Buffer
+	frame   int // partition of the current frame.
+	buffer  int // buffer used by the current frame.
+	offset  int // offset within the partition of the current frame.
+}
+
+// RingSlice of a buffer, handed out by a [Ring].
+type RingSlice struct {
+	Buffer Buffer // either a [UniformBuffer] or a [StorageBuffer].
+	Offset int    // in bytes, aligned to [LimitMinUniformBufferOffsetAlignment].
+	Size   int    // in bytes.
+}
+
+// Write the data to the start of the slice.
+func (s RingSlice) Write(data []byte) error {
+	if len(data) > s.Size {
+		return fmt.Errorf("rd: writing %d bytes to a ring slice of %d bytes", len(data), s.Size)
+	}
+	_, err := s.Buffer.WriteAt(data, int64(s.Offset))
+	return err
+}
+
+/*
+NewUniformRing returns a ring of uniform buffers, where each frame can allocate up to size bytes from each
+buffer. The size is reduced if the partitions of a buffer would exceed the [LimitMaxUniformBufferSize] of the
+device, and rounded down to the [LimitMinUniformBufferOffsetAlignment]. Returns an error if nothing remains.
+*/
+func NewUniformRing(device Interface, size int) (*Ring, error) {
+	return newRing(device, size, device.Limit(LimitMaxUniformBufferSize), func(size int) Buffer {
+		return device.UniformBuffer(make([]byte, size))
+	})
+}
+
+// NewStorageRing returns a ring of storage buffers with the given usage, where each frame can allocate up to
+// size bytes from each buffer. Returns an error if size is smaller than the offset alignment.
+func NewStorageRing(device Interface, usage StorageBufferUsage, size int) (*Ring, error) {
+	return newRing(device, size, 0, func(size int) Buffer {
+		return device.StorageBuffer(usage, make([]byte, size))
+	})
+}
+
+func newRing(device Interface, size, limit int, create func(int) Buffer) (*Ring, error) {
+	r := &Ring{
+		create: create,
+		align:  max(device.Limit(LimitMinUniformBufferOffsetAlignment), 1),
+		frames: max(device.FrameDelay(), 1),
+	}
+	if limit > 0 {
+		size = min(size, limit/r.frames)
+	}
+	r.partition = size - size%r.align
+	if r.partition <= 0 {
+		return nil, fmt.Errorf("rd: ring partitions of %d bytes are smaller than the offset alignment of %d bytes", size, r.align)
+	}
+	return r, nil
+}
+
+// Allocate returns a slice of size bytes that can be used until the end of the current frame. Returns an error
+// if size exceeds the partition size of the ring.
+func (r *Ring) Allocate(size int) (RingSlice, error) {
+	if size < 0 {
+		return RingSlice{}, fmt.Errorf("rd: negative ring slice size %d", size)
+	}
+	if size > r.partition {
+		return RingSlice{}, fmt.Errorf("rd: ring slice of %d bytes exceeds the partition size of %d bytes", size, r.partition)
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	offset := (r.offset + r.align - 1) / r.align * r.align
+	if offset+size > r.partition {
+		r.buffer++
+		offset = 0
+	}
+	if r.buffer == len(r.buffers) {
+		r.buffers = append(r.buffers, r.create(r.partition*r.frames))
+	}
+	r.offset = offset + size
+	return RingSlice{
+		Buffer: r.buffers[r.buffer],
+		Offset: r.frame*r.partition + offset,
+		Size:   size,
+	}, nil
+}
+
+// Write allocates a slice for the data, see [Ring.Allocate], and writes the data to it.
+func (r *Ring) Write(data []byte) (RingSlice, error) {
+	slice, err := r.Allocate(len(data))
+	if err != nil {
+		return slice, err
+	}
+	return slice, slice.Write(data)
+}
+
+// Advance marks the end of the current frame, such that the next frame allocates from the partitions of the
+// frame that ended [Interface.FrameDelay] frames ago. It should be called once per frame.
+func (r *Ring) Advance() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.frame = (r.frame + 1) % r.frames
+	r.buffer, r.offset = 0, 0
+}
+
+// Buffers returns the buffers of the ring, such that variables can be created for each of them.
+func (r *Ring) Buffers() []Buffer {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]Buffer(nil), r.buffers...)
+}
+
+// Free the buffers of the ring. Any slices handed out by the ring must no longer be in use.
+func (r *Ring) Free() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, buffer := range r.buffers {
+		buffer.Free()
+	}
+	r.buffers = nil
+	r.buffer, r.offset = 0, 0
+}
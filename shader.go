@@ -1,5 +1,11 @@
 package rd
 
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
 // Shader reference.
 type Shader interface {
 	Resource
@@ -97,3 +103,103 @@ type InputAttachment struct {
 
 	Texture Texture
 }
+
+/*
+Constants maps the IDs of specialization constants (layout(constant_id = N) in GLSL) to their values.
+These can be used in place of the untyped defines passed to [Interface.Processor] and
+[RenderingOptions.ShaderDefines].
+
+	defines, err := rd.Constants{
+		0: rd.ConstantBool(true),
+		1: rd.ConstantFloat(0.5),
+	}.Defines()
+*/
+type Constants map[int]Constant
+
+// Defines returns the constants as defines, where the index of each define is the ID of the constant.
+// IDs without a constant are left as nil. Returns an error if any of the IDs are negative.
+func (c Constants) Defines() ([]any, error) {
+	size := 0
+	for id := range c {
+		if id < 0 {
+			return nil, fmt.Errorf("rd: specialization constant ID %d is negative", id)
+		}
+		size = max(size, id+1)
+	}
+	defines := make([]any, size)
+	for id, constant := range c {
+		defines[id] = constant.Value()
+	}
+	return defines, nil
+}
+
+// Constant is a typed specialization constant value.
+type Constant struct {
+	kind ConstantType
+	bits uint32
+}
+
+// ConstantType of a specialization constant.
+type ConstantType int
+
+const (
+	ConstantTypeBool  ConstantType = iota // bool
+	ConstantTypeInt                       // int32
+	ConstantTypeUint                      // uint32
+	ConstantTypeFloat                     // float32
+)
+
+// String returns the GLSL name of the type.
+func (t ConstantType) String() string {
+	switch t {
+	case ConstantTypeBool:
+		return "bool"
+	case ConstantTypeInt:
+		return "int"
+	case ConstantTypeUint:
+		return "uint"
+	case ConstantTypeFloat:
+		return "float"
+	default:
+		return "ConstantType(" + strconv.Itoa(int(t)) + ")"
+	}
+}
+
+// ConstantBool returns a boolean specialization constant.
+func ConstantBool(v bool) Constant {
+	if v {
+		return Constant{kind: ConstantTypeBool, bits: 1}
+	}
+	return Constant{kind: ConstantTypeBool}
+}
+
+// ConstantInt returns a signed integer specialization constant.
+func ConstantInt(v int32) Constant { return Constant{kind: ConstantTypeInt, bits: uint32(v)} }
+
+// ConstantUint returns an unsigned integer specialization constant.
+func ConstantUint(v uint32) Constant { return Constant{kind: ConstantTypeUint, bits: v} }
+
+// ConstantFloat returns a floating-point specialization constant.
+func ConstantFloat(v float32) Constant {
+	return Constant{kind: ConstantTypeFloat, bits: math.Float32bits(v)}
+}
+
+// Type of the constant.
+func (c Constant) Type() ConstantType { return c.kind }
+
+// Bits returns the 32-bit representation of the constant, as passed to the shader.
+func (c Constant) Bits() uint32 { return c.bits }
+
+// Value returns the constant as a bool, int32, uint32 or float32.
+func (c Constant) Value() any {
+	switch c.kind {
+	case ConstantTypeBool:
+		return c.bits != 0
+	case ConstantTypeInt:
+		return int32(c.bits)
+	case ConstantTypeUint:
+		return c.bits
+	default:
+		return math.Float32frombits(c.bits)
+	}
+}
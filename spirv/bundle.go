@@ -0,0 +1,254 @@
+package spirv
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/fs"
+	"sort"
+
+	"grow.graphics/rd"
+)
+
+/*
+Bundle of SPIR-V modules, one for each shader stage, that can be shipped with a program without a rendering
+device. The stages of a bundle are identified by their [ExecutionModel] and can be serialized with
+[Bundle.MarshalBinary] into a compact, versioned and checksummed file that can later be loaded with
+[LoadBundle] (for example, from an [embed.FS]).
+
+	//go:embed shaders/blur.spv
+	var shaders embed.FS
+
+	bundle, err := spirv.LoadBundle(shaders, "shaders/blur.spv")
+	if err != nil {
+		return err
+	}
+	binary := RD.CompileSPIRV("blur", bundle.For(RD))
+*/
+type Bundle map[ExecutionModel][]uint32
+
+// bundle file format, all integers are little endian:
+//
+//	magic    [4]byte "RDSV"
+//	version  uint16
+//	flags    uint16
+//	payload  (deflate compressed when flags&bundleDeflate != 0)
+//		count uint32
+//		count * (model uint32, words uint32, [words]uint32)
+//	checksum uint32 (CRC-32C of everything before it)
+const (
+	bundleMagic   = "RDSV"
+	bundleVersion = 1
+	bundleDeflate = 1 << 0
+	bundleStages  = 5 // maximum count, one for each stage of an [rd.SPIRV].
+)
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// NewBundle copies each of the stages of the given SPIR-V into a [Bundle].
+func NewBundle(spirv rd.SPIRV) (Bundle, error) {
+	bundle := make(Bundle)
+	for model, code := range map[ExecutionModel][]byte{
+		ExecutionModelVertex:                 spirv.Vertex(),
+		ExecutionModelFragment:               spirv.Fragment(),
+		ExecutionModelTessellationControl:    spirv.TesselationControl(),
+		ExecutionModelTessellationEvaluation: spirv.TesselationEvaluation(),
+		ExecutionModelGLCompute:              spirv.Compute(),
+	} {
+		if len(code) == 0 {
+			continue
+		}
+		words, err := Words(code)
+		if err != nil {
+			return nil, fmt.Errorf("spirv: %v stage: %w", model, err)
+		}
+		if words[0] != Magic {
+			return nil, fmt.Errorf("spirv: %v stage: invalid magic number %#08x", model, words[0])
+		}
+		bundle[model] = words
+	}
+	return bundle, nil
+}
+
+// LoadBundle reads a [Bundle] from the named file in the given file system.
+func LoadBundle(fsys fs.FS, name string) (Bundle, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	var bundle Bundle
+	if err := bundle.UnmarshalBinary(data); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return bundle, nil
+}
+
+// ReadBundle reads a serialized [Bundle] from r.
+func ReadBundle(r io.Reader) (Bundle, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var bundle Bundle
+	return bundle, bundle.UnmarshalBinary(data)
+}
+
+// WriteTo writes the serialized bundle to w.
+func (b Bundle) WriteTo(w io.Writer) (int64, error) {
+	data, err := b.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(data)
+	return int64(n), err
+}
+
+// MarshalBinary implements [encoding.BinaryMarshaler].
+func (b Bundle) MarshalBinary() ([]byte, error) {
+	if len(b) > bundleStages {
+		return nil, fmt.Errorf("spirv: bundle has %d stages, more than the %d of a shader", len(b), bundleStages)
+	}
+	models := make([]ExecutionModel, 0, len(b))
+	for model := range b {
+		models = append(models, model)
+	}
+	sort.Slice(models, func(i, j int) bool { return models[i] < models[j] })
+
+	var payload bytes.Buffer
+	payload.Grow(4 + len(b)*8)
+	binary.Write(&payload, binary.LittleEndian, uint32(len(models)))
+	for _, model := range models {
+		words := b[model]
+		binary.Write(&payload, binary.LittleEndian, [2]uint32{uint32(model), uint32(len(words))})
+		binary.Write(&payload, binary.LittleEndian, words)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(bundleMagic)
+	binary.Write(&buf, binary.LittleEndian, [2]uint16{bundleVersion, bundleDeflate})
+	compressor, err := flate.NewWriter(&buf, flate.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := compressor.Write(payload.Bytes()); err != nil {
+		return nil, err
+	}
+	if err := compressor.Close(); err != nil {
+		return nil, err
+	}
+	binary.Write(&buf, binary.LittleEndian, crc32.Checksum(buf.Bytes(), castagnoli))
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements [encoding.BinaryUnmarshaler].
+func (b *Bundle) UnmarshalBinary(data []byte) error {
+	if len(data) < 12 || string(data[:4]) != bundleMagic {
+		return errors.New("spirv: not a SPIR-V bundle")
+	}
+	body, checksum := data[:len(data)-4], binary.LittleEndian.Uint32(data[len(data)-4:])
+	if crc32.Checksum(body, castagnoli) != checksum {
+		return errors.New("spirv: bundle checksum mismatch")
+	}
+	version, flags := binary.LittleEndian.Uint16(body[4:]), binary.LittleEndian.Uint16(body[6:])
+	if version != bundleVersion {
+		return fmt.Errorf("spirv: unsupported bundle version %d", version)
+	}
+	var payload io.Reader = bytes.NewReader(body[8:])
+	if flags&bundleDeflate != 0 {
+		payload = flate.NewReader(payload)
+	}
+	var count uint32
+	if err := binary.Read(payload, binary.LittleEndian, &count); err != nil {
+		return fmt.Errorf("spirv: corrupt bundle: %w", err)
+	}
+	if count > bundleStages {
+		return fmt.Errorf("spirv: corrupt bundle: %d stages", count)
+	}
+	bundle := make(Bundle, count)
+	for i := uint32(0); i < count; i++ {
+		var header [2]uint32
+		if err := binary.Read(payload, binary.LittleEndian, &header); err != nil {
+			return fmt.Errorf("spirv: corrupt bundle: %w", err)
+		}
+		words := make([]uint32, 0, min(header[1], 1<<20))
+		for len(words) < int(header[1]) {
+			chunk := make([]uint32, min(int(header[1])-len(words), 1<<16))
+			if err := binary.Read(payload, binary.LittleEndian, chunk); err != nil {
+				return fmt.Errorf("spirv: corrupt bundle: %w", err)
+			}
+			words = append(words, chunk...)
+		}
+		if len(words) == 0 || words[0] != Magic {
+			return fmt.Errorf("spirv: bundle stage %v is not a SPIR-V module", ExecutionModel(header[0]))
+		}
+		if _, ok := bundle[ExecutionModel(header[0])]; ok {
+			return fmt.Errorf("spirv: corrupt bundle: duplicate %v stage", ExecutionModel(header[0]))
+		}
+		bundle[ExecutionModel(header[0])] = words
+	}
+	if _, err := io.ReadFull(payload, make([]byte, 1)); err != io.EOF {
+		return errors.New("spirv: corrupt bundle: unexpected data after the last stage")
+	}
+	*b = bundle
+	return nil
+}
+
+// Bytes returns the little endian binary representation of the given stage, or nil if the
+// bundle doesn't include it.
+func (b Bundle) Bytes(model ExecutionModel) []byte {
+	words := b[model]
+	if len(words) == 0 {
+		return nil
+	}
+	code := make([]byte, len(words)*4)
+	for i, word := range words {
+		binary.LittleEndian.PutUint32(code[i*4:], word)
+	}
+	return code
+}
+
+func (b Bundle) Compute() []byte               { return b.Bytes(ExecutionModelGLCompute) }
+func (b Bundle) Fragment() []byte              { return b.Bytes(ExecutionModelFragment) }
+func (b Bundle) TesselationControl() []byte    { return b.Bytes(ExecutionModelTessellationControl) }
+func (b Bundle) TesselationEvaluation() []byte { return b.Bytes(ExecutionModelTessellationEvaluation) }
+func (b Bundle) Vertex() []byte                { return b.Bytes(ExecutionModelVertex) }
+
+// For returns the bundle as an [rd.SPIRV] for the given device, such that it can be passed
+// to [rd.Interface.CompileSPIRV].
+func (b Bundle) For(device rd.Interface) rd.SPIRV {
+	return deviceBundle{Bundle: b, device: device}
+}
+
+type deviceBundle struct {
+	Bundle
+	device rd.Interface
+}
+
+// Shader compiles the bundle into a new shader on the device.
+func (b deviceBundle) Shader(name string) rd.Shader {
+	shader := b.device.Shader()
+	shader.SetResourceName(name)
+	shader.Compile(b.device.CompileSPIRV(name, b))
+	return shader
+}
+
+// Reflect on each stage of the bundle, see [Module.Reflect].
+func (b Bundle) Reflect() (map[ExecutionModel]*Reflection, error) {
+	reflections := make(map[ExecutionModel]*Reflection, len(b))
+	for model, words := range b {
+		module, err := ParseWords(words)
+		if err != nil {
+			return nil, fmt.Errorf("spirv: %v stage: %w", model, err)
+		}
+		reflection, err := module.Reflect()
+		if err != nil {
+			return nil, fmt.Errorf("spirv: %v stage: %w", model, err)
+		}
+		reflections[model] = reflection
+	}
+	return reflections, nil
+}
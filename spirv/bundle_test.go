@@ -0,0 +1,50 @@
+package spirv
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"testing"
+)
+
+// rawBundle serializes an uncompressed bundle with the given payload words.
+func rawBundle(payload ...uint32) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(bundleMagic)
+	binary.Write(&buf, binary.LittleEndian, [2]uint16{bundleVersion, 0})
+	binary.Write(&buf, binary.LittleEndian, payload)
+	binary.Write(&buf, binary.LittleEndian, crc32.Checksum(buf.Bytes(), castagnoli))
+	return buf.Bytes()
+}
+
+func TestBundleRoundTrip(t *testing.T) {
+	bundle := Bundle{ExecutionModelGLCompute: computeModule().Words()}
+	data, err := bundle.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Bundle
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !equal(decoded[ExecutionModelGLCompute], bundle[ExecutionModelGLCompute]) {
+		t.Error("the compute stage changed")
+	}
+}
+
+func TestBundleCorrupt(t *testing.T) {
+	vertex := uint32(ExecutionModelVertex)
+	for name, data := range map[string][]byte{
+		"count":     rawBundle(0xffffffff),
+		"duplicate": rawBundle(2, vertex, 1, Magic, vertex, 1, Magic),
+		"trailing":  rawBundle(1, vertex, 1, Magic, 0),
+	} {
+		var bundle Bundle
+		if err := bundle.UnmarshalBinary(data); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+	if err := new(Bundle).UnmarshalBinary(rawBundle(1, vertex, 1, Magic)); err != nil {
+		t.Error(err)
+	}
+}
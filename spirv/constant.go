@@ -0,0 +1,155 @@
+package spirv
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"grow.graphics/rd"
+)
+
+// constantType returns the [rd.ConstantType] that corresponds to a reflected specialization constant.
+func (sc *SpecializationConstant) constantType() (rd.ConstantType, bool) {
+	switch t := sc.Type; {
+	case t.Kind == KindBool:
+		return rd.ConstantTypeBool, true
+	case t.Kind == KindInt && t.Width == 32 && t.Signed:
+		return rd.ConstantTypeInt, true
+	case t.Kind == KindInt && t.Width == 32:
+		return rd.ConstantTypeUint, true
+	case t.Kind == KindFloat && t.Width == 32:
+		return rd.ConstantTypeFloat, true
+	default:
+		return 0, false
+	}
+}
+
+// specializationConstants returns the specialization constants declared by any of the reflections, along
+// with an error for each constant that is declared with different types by different stages.
+func specializationConstants(reflections []*Reflection) (map[uint32]*SpecializationConstant, error) {
+	declared := make(map[uint32]*SpecializationConstant)
+	var errs []error
+	for _, r := range reflections {
+		if r == nil {
+			continue
+		}
+		for i := range r.SpecializationConstants {
+			sc := &r.SpecializationConstants[i]
+			if other, ok := declared[sc.ID]; ok {
+				if a, b := other.Type, sc.Type; a.Kind != b.Kind || a.Width != b.Width || a.Signed != b.Signed {
+					errs = append(errs, fmt.Errorf("specialization constant %d is declared as both %v (%s) and %v (%s)", sc.ID, a, other.Name, b, sc.Name))
+				}
+				continue
+			}
+			declared[sc.ID] = sc
+		}
+	}
+	return declared, errors.Join(errs...)
+}
+
+/*
+Specialize checks the constants against the specialization constants declared by the reflected stages of a
+shader and then converts them into defines, for use with [rd.Interface.Processor] or
+[rd.RenderingOptions.ShaderDefines]. Any declared constant without a value is set to its default value, so
+that the IDs of the defines line up with the IDs of the constants.
+
+Returns an error for each constant with an ID that isn't declared by the shader, or with a different type,
+and for each constant that the stages declare with different types.
+*/
+func Specialize(constants rd.Constants, reflections ...*Reflection) ([]any, error) {
+	declared, err := specializationConstants(reflections)
+	var errs []error
+	if err != nil {
+		errs = append(errs, err)
+	}
+	size := 0
+	for id, constant := range constants {
+		sc, ok := declared[uint32(id)]
+		if id < 0 || !ok {
+			errs = append(errs, fmt.Errorf("specialization constant %d is not declared by the shader", id))
+			continue
+		}
+		expects, ok := sc.constantType()
+		if !ok || expects != constant.Type() {
+			errs = append(errs, fmt.Errorf("specialization constant %d (%s) expects %v but was given %v", id, sc.Name, sc.Type, constant.Type()))
+			continue
+		}
+		size = max(size, id+1)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	for id := range declared {
+		size = max(size, int(id)+1)
+	}
+	defines := make([]any, size)
+	for id, sc := range declared {
+		defines[id] = sc.defaultValue()
+	}
+	for id, constant := range constants {
+		defines[id] = constant.Value()
+	}
+	return defines, nil
+}
+
+// defaultValue returns the default value of the constant as a bool, int32, uint32 or float32.
+func (sc *SpecializationConstant) defaultValue() any {
+	kind, ok := sc.constantType()
+	if !ok {
+		return nil
+	}
+	switch kind {
+	case rd.ConstantTypeBool:
+		return sc.Default != 0
+	case rd.ConstantTypeInt:
+		return int32(sc.Default)
+	case rd.ConstantTypeUint:
+		return uint32(sc.Default)
+	default:
+		return math.Float32frombits(uint32(sc.Default))
+	}
+}
+
+/*
+CheckDefines checks untyped defines against the specialization constants declared by the reflected stages of
+a shader, where the index of each define is the ID of the constant. Nil defines are ignored.
+*/
+func CheckDefines(defines []any, reflections ...*Reflection) error {
+	declared, err := specializationConstants(reflections)
+	var errs []error
+	if err != nil {
+		errs = append(errs, err)
+	}
+	for id, define := range defines {
+		if define == nil {
+			continue
+		}
+		sc, ok := declared[uint32(id)]
+		if !ok {
+			errs = append(errs, fmt.Errorf("specialization constant %d is not declared by the shader", id))
+			continue
+		}
+		expects, ok := sc.constantType()
+		if !ok {
+			continue
+		}
+		var given rd.ConstantType
+		switch define.(type) {
+		case bool:
+			given = rd.ConstantTypeBool
+		case int32, int, int8, int16, int64:
+			given = rd.ConstantTypeInt
+		case uint32, uint, uint8, uint16, uint64:
+			given = rd.ConstantTypeUint
+		case float32, float64:
+			given = rd.ConstantTypeFloat
+		default:
+			errs = append(errs, fmt.Errorf("specialization constant %d (%s) cannot be set to a %T", id, sc.Name, define))
+			continue
+		}
+		if given != expects {
+			errs = append(errs, fmt.Errorf("specialization constant %d (%s) expects %v but was given %T", id, sc.Name, sc.Type, define))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,25 @@
+package spirv
+
+import (
+	"testing"
+
+	"grow.graphics/rd"
+)
+
+func TestSpecializeConflictingStages(t *testing.T) {
+	vertex := &Reflection{SpecializationConstants: []SpecializationConstant{
+		{ID: 0, Name: "count", Type: &Type{Kind: KindInt, Width: 32, Signed: true}},
+	}}
+	fragment := &Reflection{SpecializationConstants: []SpecializationConstant{
+		{ID: 0, Name: "scale", Type: &Type{Kind: KindFloat, Width: 32}},
+	}}
+	if _, err := Specialize(rd.Constants{0: rd.ConstantInt(1)}, vertex, fragment); err == nil {
+		t.Error("constant declared as both int and float was accepted")
+	}
+	if err := CheckDefines([]any{int32(1)}, vertex, fragment); err == nil {
+		t.Error("define of a constant declared as both int and float was accepted")
+	}
+	if _, err := Specialize(rd.Constants{0: rd.ConstantInt(1)}, vertex, vertex); err != nil {
+		t.Error(err)
+	}
+}
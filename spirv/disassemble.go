@@ -0,0 +1,412 @@
+package spirv
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// DisassembleOptions control the text produced by [Module.Disassemble].
+type DisassembleOptions struct {
+	RawIDs   bool // print numeric <id>s, rather than friendly names derived from debug names and types.
+	NoHeader bool // omit the header comments.
+	NoIndent bool // do not align instructions on the '=' of their result.
+}
+
+// generators maps the vendor in the high 16 bits of the generator magic number to the tool name used by spirv-dis.
+var generators = [...]string{
+	0: "Khronos", 1: "LunarG", 2: "Valve", 3: "Codeplay", 4: "NVIDIA", 5: "ARM",
+	6:  "Khronos LLVM/SPIR-V Translator",
+	7:  "Khronos SPIR-V Tools Assembler",
+	8:  "Khronos Glslang Reference Front End",
+	9:  "Qualcomm",
+	10: "AMD",
+	11: "Intel",
+	12: "Imagination",
+	13: "Google Shaderc over Glslang",
+	14: "Google spiregg",
+	15: "Google rspirv",
+	16: "X-LEGEND Mesa-IR/SPIR-V Translator",
+	17: "Khronos SPIR-V Tools Linker",
+	18: "Wine VKD3D Shader Compiler",
+	19: "Tellusim Clay Shader Compiler",
+	20: "W3C WebGPU Group WHLSL Shader Translator",
+	21: "Google Clspv",
+	22: "Google MLIR SPIR-V Serializer",
+	23: "Google Tint Compiler",
+	24: "Google ANGLE Shader Compiler",
+	25: "Netease Games Messiah Shader Compiler",
+	26: "Xenia Xenia Emulator Microcode Translator",
+	27: "Embark Studios Rust GPU Compiler Backend",
+	28: "gfx-rs community Naga",
+	29: "Mikkosoft Productions MSP Shader Compiler",
+	30: "SpvGenTwo community SpvGenTwo SPIR-V IR Tools",
+	31: "Google Skia SkSL",
+	32: "TornadoVM Beehive SPIRV Toolkit",
+	33: "DragonJoker ShaderWriter",
+	34: "Rayan Hatout SPIRVSmith",
+	35: "Saarland University Shady",
+	36: "Taichi Graphics Taichi",
+	37: "heroseh Hero C Compiler",
+	38: "Meta SparkSL",
+	39: "SirLynix Nazara ShaderLang Compiler",
+	40: "NVIDIA Slang Compiler",
+}
+
+// glslStd450 names the instructions of the GLSL.std.450 extended instruction set.
+var glslStd450 = strings.Fields(`_ Round RoundEven Trunc FAbs SAbs FSign SSign Floor Ceil Fract Radians Degrees
+	Sin Cos Tan Asin Acos Atan Sinh Cosh Tanh Asinh Acosh Atanh Atan2 Pow Exp Log Exp2 Log2 Sqrt InverseSqrt
+	Determinant MatrixInverse Modf ModfStruct FMin UMin SMin FMax UMax SMax FClamp UClamp SClamp FMix IMix Step
+	SmoothStep Fma Frexp FrexpStruct Ldexp PackSnorm4x8 PackUnorm4x8 PackSnorm2x16 PackUnorm2x16 PackHalf2x16
+	PackDouble2x32 UnpackSnorm2x16 UnpackUnorm2x16 UnpackHalf2x16 UnpackSnorm4x8 UnpackUnorm4x8 UnpackDouble2x32
+	Length Distance Cross Normalize FaceForward Reflect Refract FindILsb FindSMsb FindUMsb InterpolateAtCentroid
+	InterpolateAtSample InterpolateAtOffset NMin NMax NClamp`)
+
+// disassembler holds the state needed to print the instructions of a module.
+type disassembler struct {
+	module  *Module
+	options DisassembleOptions
+	names   map[uint32]string
+	sets    map[uint32]string // extended instruction sets by <id>.
+	index
+}
+
+/*
+Disassemble writes the module as text that is compatible with the output of spirv-dis (and can be
+assembled again by spirv-as). Unless RawIDs is set, <id>s are given the same friendly names that
+spirv-dis would use, based on their debug names, types and constant values.
+*/
+func (m *Module) Disassemble(w io.Writer, options DisassembleOptions) error {
+	d := disassembler{
+		module:  m,
+		options: options,
+		sets:    make(map[uint32]string),
+		index:   newIndex(m),
+	}
+	for _, inst := range m.Instructions {
+		if inst.Opcode == OpExtInstImport && len(inst.Operands) > 1 {
+			d.sets[inst.Operands[0]], _ = String(inst.Operands[1:])
+		}
+	}
+	if !options.RawIDs {
+		d.friendlyNames()
+	}
+	out := bufio.NewWriter(w)
+	if !options.NoHeader {
+		generator := fmt.Sprintf("Unknown(%d)", m.Generator>>16)
+		if vendor := m.Generator >> 16; int(vendor) < len(generators) && generators[vendor] != "" {
+			generator = generators[vendor]
+		}
+		fmt.Fprintf(out, "; SPIR-V\n; Version: %d.%d\n; Generator: %s; %d\n; Bound: %d\n; Schema: %d\n",
+			m.Version>>16&0xff, m.Version>>8&0xff, generator, m.Generator&0xffff, m.Bound, m.Schema)
+	}
+	for i, inst := range m.Instructions {
+		line, err := d.instruction(inst)
+		if err != nil {
+			return fmt.Errorf("%w (instruction %d)", err, i)
+		}
+		out.WriteString(line)
+		out.WriteByte('\n')
+	}
+	return out.Flush()
+}
+
+// instruction formats a single instruction.
+func (d *disassembler) instruction(inst Instruction) (string, error) {
+	operands, err := inst.decode(d.selectorWidth)
+	if err != nil {
+		return "", err
+	}
+	var (
+		buf    strings.Builder
+		result string
+		rtype  uint32
+	)
+	buf.WriteString(inst.Opcode.String())
+	for _, operand := range operands {
+		switch operand.kind {
+		case operandResult:
+			result = d.name(operand.words[0])
+			continue
+		case operandResultType:
+			rtype = operand.words[0]
+		}
+		buf.WriteByte(' ')
+		buf.WriteString(d.operand(inst, operand, rtype))
+	}
+	switch {
+	case d.options.NoIndent && result != "":
+		return result + " = " + buf.String(), nil
+	case d.options.NoIndent:
+		return buf.String(), nil
+	case result != "":
+		return fmt.Sprintf("%12s = %s", result, buf.String()), nil
+	default:
+		return strings.Repeat(" ", 15) + buf.String(), nil
+	}
+}
+
+// operand formats a single operand of an instruction with the given result type.
+func (d *disassembler) operand(inst Instruction, operand operand, rtype uint32) string {
+	switch operand.kind {
+	case operandResultType, operandID, operandResult:
+		return d.name(operand.words[0])
+	case operandString:
+		s, _ := String(operand.words)
+		return quote(s)
+	case operandNumber:
+		return d.number(rtype, operand.words)
+	case operandExtInst:
+		if len(inst.Operands) > 2 && d.sets[inst.Operands[2]] == "GLSL.std.450" {
+			if n := operand.words[0]; n > 0 && int(n) < len(glslStd450) {
+				return glslStd450[n]
+			}
+		}
+	case operandSpecConstantOp:
+		if info, ok := grammar[Opcode(operand.words[0])]; ok {
+			return info.name
+		}
+	case operandEnum:
+		return operand.enum.String(operand.words[0])
+	case operandBitmask:
+		return mask(operand.enum, operand.words[0])
+	}
+	if len(operand.words) == 2 {
+		return strconv.FormatUint(uint64(operand.words[1])<<32|uint64(operand.words[0]), 10)
+	}
+	return strconv.FormatUint(uint64(operand.words[0]), 10)
+}
+
+// number formats a literal number according to its numeric type.
+func (d *disassembler) number(rtype uint32, words []uint32) string {
+	var value uint64
+	for i, word := range words {
+		value |= uint64(word) << (32 * i)
+	}
+	decl := d.types[rtype]
+	for decl.Opcode == OpTypeVector && len(decl.Operands) > 1 {
+		decl = d.types[decl.Operands[1]]
+	}
+	switch {
+	case decl.Opcode == OpTypeInt && len(decl.Operands) > 2 && decl.Operands[2] == 1:
+		width := min(max(decl.Operands[1], 1), 64)
+		return strconv.FormatInt(int64(value<<(64-width))>>(64-width), 10)
+	case decl.Opcode == OpTypeFloat && len(decl.Operands) > 1 && decl.Operands[1] == 32:
+		return formatFloat(float64(math.Float32frombits(uint32(value))), 32)
+	case decl.Opcode == OpTypeFloat && len(decl.Operands) > 1 && decl.Operands[1] == 64:
+		return formatFloat(math.Float64frombits(value), 64)
+	case decl.Opcode == OpTypeFloat && len(decl.Operands) > 1 && decl.Operands[1] == 16:
+		return formatFloat(half(uint16(value)), 16)
+	}
+	return strconv.FormatUint(value, 10)
+}
+
+// name returns the name used to refer to an <id>.
+func (d *disassembler) name(id uint32) string {
+	if name, ok := d.names[id]; ok {
+		return "%" + name
+	}
+	return "%" + strconv.FormatUint(uint64(id), 10)
+}
+
+// friendlyNames assigns names to <id>s, following the same rules as spirv-dis.
+func (d *disassembler) friendlyNames() {
+	d.names = make(map[uint32]string)
+	used := make(map[string]bool)
+	assign := func(id uint32, name string) {
+		if _, ok := d.names[id]; ok {
+			return
+		}
+		name = sanitize(name)
+		if used[name] {
+			base := name
+			for i := 0; used[name]; i++ {
+				name = base + "_" + strconv.Itoa(i)
+			}
+		}
+		used[name] = true
+		d.names[id] = name
+	}
+	lookup := func(id uint32) string {
+		if name, ok := d.names[id]; ok {
+			return name
+		}
+		return strconv.FormatUint(uint64(id), 10)
+	}
+	for _, inst := range d.module.Instructions {
+		if inst.Opcode == OpName && len(inst.Operands) > 1 {
+			name, _ := String(inst.Operands[1:])
+			assign(inst.Operands[0], name)
+		}
+	}
+	for _, inst := range d.module.Instructions {
+		ops := inst.Operands
+		switch inst.Opcode {
+		case OpTypeVoid:
+			if len(ops) > 0 {
+				assign(ops[0], "void")
+			}
+		case OpTypeBool:
+			if len(ops) > 0 {
+				assign(ops[0], "bool")
+			}
+		case OpTypeInt:
+			if len(ops) > 2 {
+				assign(ops[0], intName(ops[1], ops[2] == 1))
+			}
+		case OpTypeFloat:
+			if len(ops) > 1 {
+				switch ops[1] {
+				case 16:
+					assign(ops[0], "half")
+				case 32:
+					assign(ops[0], "float")
+				case 64:
+					assign(ops[0], "double")
+				default:
+					assign(ops[0], "fp"+strconv.FormatUint(uint64(ops[1]), 10))
+				}
+			}
+		case OpTypeVector:
+			if len(ops) > 2 {
+				assign(ops[0], "v"+strconv.FormatUint(uint64(ops[2]), 10)+lookup(ops[1]))
+			}
+		case OpTypeMatrix:
+			if len(ops) > 2 {
+				assign(ops[0], "mat"+strconv.FormatUint(uint64(ops[2]), 10)+lookup(ops[1]))
+			}
+		case OpTypeArray:
+			if len(ops) > 2 {
+				assign(ops[0], "_arr_"+lookup(ops[1])+"_"+lookup(ops[2]))
+			}
+		case OpTypeRuntimeArray:
+			if len(ops) > 1 {
+				assign(ops[0], "_runtimearr_"+lookup(ops[1]))
+			}
+		case OpTypePointer:
+			if len(ops) > 2 {
+				assign(ops[0], "_ptr_"+enumStorageClass.String(ops[1])+"_"+lookup(ops[2]))
+			}
+		case OpTypeStruct:
+			if len(ops) > 0 {
+				assign(ops[0], "_struct_"+strconv.FormatUint(uint64(ops[0]), 10))
+			}
+		case OpTypeAccelerationStructureKHR:
+			if len(ops) > 0 {
+				assign(ops[0], "accelerationStructure")
+			}
+		case OpConstantTrue:
+			if len(ops) > 1 {
+				assign(ops[1], "true")
+			}
+		case OpConstantFalse:
+			if len(ops) > 1 {
+				assign(ops[1], "false")
+			}
+		case OpConstant:
+			if len(ops) > 2 {
+				value := d.number(ops[0], ops[2:])
+				value = strings.NewReplacer("-", "n", ".", "_", "+", "_").Replace(value)
+				assign(ops[1], lookup(ops[0])+"_"+value)
+			}
+		}
+	}
+}
+
+// intName returns the friendly name of an integer type.
+func intName(width uint32, signed bool) string {
+	var name string
+	switch width {
+	case 8:
+		name = "char"
+	case 16:
+		name = "short"
+	case 32:
+		name = "int"
+	case 64:
+		name = "long"
+	default:
+		name = "int" + strconv.FormatUint(uint64(width), 10)
+	}
+	if !signed {
+		name = "u" + name
+	}
+	return name
+}
+
+// sanitize replaces any characters that are not valid within an <id> name.
+func sanitize(name string) string {
+	if name == "" {
+		return "_"
+	}
+	return strings.Map(func(r rune) rune {
+		if r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// mask formats a bitmask operand as the names of its bits, separated by '|'.
+func mask(e *enum, value uint32) string {
+	if value == 0 {
+		return "None"
+	}
+	var names []string
+	for bit := uint32(1); bit != 0; bit <<= 1 {
+		if value&bit == 0 {
+			continue
+		}
+		if name, ok := e.values[bit]; ok {
+			names = append(names, name)
+		} else {
+			names = append(names, fmt.Sprintf("0x%x", bit))
+		}
+	}
+	return strings.Join(names, "|")
+}
+
+// quote a literal string, escaping any quotes and backslashes.
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// formatFloat formats a floating-point literal with enough precision to be assembled to the same value, printing
+// infinities and NaNs as hexadecimal floats, as spirv-dis does.
+func formatFloat(f float64, bits int) string {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		exponent := map[int]int{16: 16, 32: 128, 64: 1024}[bits]
+		sign := ""
+		if math.Signbit(f) {
+			sign = "-"
+		}
+		if math.IsNaN(f) {
+			return fmt.Sprintf("%s0x1.8p+%d", sign, exponent)
+		}
+		return fmt.Sprintf("%s0x1p+%d", sign, exponent)
+	}
+	precision := map[int]int{16: 5, 32: 9, 64: 17}[bits]
+	return strconv.FormatFloat(f, 'g', precision, 64)
+}
+
+// half converts an IEEE 754 binary16 value to a float64.
+func half(h uint16) float64 {
+	sign := 1.0
+	if h&0x8000 != 0 {
+		sign = -1
+	}
+	exponent, fraction := int(h>>10&0x1f), float64(h&0x3ff)
+	switch exponent {
+	case 0:
+		return sign * math.Ldexp(fraction, -24)
+	case 0x1f:
+		if fraction != 0 {
+			return math.NaN()
+		}
+		return math.Inf(int(sign))
+	}
+	return sign * math.Ldexp(1+fraction/1024, exponent-15)
+}
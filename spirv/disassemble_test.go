@@ -0,0 +1,22 @@
+package spirv
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDisassembleSpecConstantOp(t *testing.T) {
+	var out strings.Builder
+	if err := computeModule().Disassemble(&out, DisassembleOptions{RawIDs: true, NoHeader: true}); err != nil {
+		t.Fatal(err)
+	}
+	if want := "%9 = OpSpecConstantOp %4 CompositeExtract %8 0\n"; !strings.Contains(out.String(), want) {
+		t.Errorf("missing %q in:\n%s", want, out.String())
+	}
+}
+
+func TestDisassembleTruncated(t *testing.T) {
+	m := &Module{Instructions: []Instruction{{OpTypeVoid, nil}, {OpTypeBool, nil}, {OpTypeAccelerationStructureKHR, nil}}}
+	var out strings.Builder
+	m.Disassemble(&out, DisassembleOptions{}) // must not panic.
+}
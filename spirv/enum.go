@@ -0,0 +1,204 @@
+package spirv
+
+import "fmt"
+
+// Opcode of an [Instruction].
+type Opcode uint16
+
+const (
+	OpNop                          Opcode = 0
+	OpUndef                        Opcode = 1
+	OpSourceContinued              Opcode = 2
+	OpSource                       Opcode = 3
+	OpSourceExtension              Opcode = 4
+	OpName                         Opcode = 5
+	OpMemberName                   Opcode = 6
+	OpString                       Opcode = 7
+	OpLine                         Opcode = 8
+	OpExtension                    Opcode = 10
+	OpExtInstImport                Opcode = 11
+	OpExtInst                      Opcode = 12
+	OpMemoryModel                  Opcode = 14
+	OpEntryPoint                   Opcode = 15
+	OpExecutionMode                Opcode = 16
+	OpCapability                   Opcode = 17
+	OpTypeVoid                     Opcode = 19
+	OpTypeBool                     Opcode = 20
+	OpTypeInt                      Opcode = 21
+	OpTypeFloat                    Opcode = 22
+	OpTypeVector                   Opcode = 23
+	OpTypeMatrix                   Opcode = 24
+	OpTypeImage                    Opcode = 25
+	OpTypeSampler                  Opcode = 26
+	OpTypeSampledImage             Opcode = 27
+	OpTypeArray                    Opcode = 28
+	OpTypeRuntimeArray             Opcode = 29
+	OpTypeStruct                   Opcode = 30
+	OpTypeOpaque                   Opcode = 31
+	OpTypePointer                  Opcode = 32
+	OpTypeFunction                 Opcode = 33
+	OpTypeForwardPointer           Opcode = 39
+	OpConstantTrue                 Opcode = 41
+	OpConstantFalse                Opcode = 42
+	OpConstant                     Opcode = 43
+	OpConstantComposite            Opcode = 44
+	OpConstantSampler              Opcode = 45
+	OpConstantNull                 Opcode = 46
+	OpSpecConstantTrue             Opcode = 48
+	OpSpecConstantFalse            Opcode = 49
+	OpSpecConstant                 Opcode = 50
+	OpSpecConstantComposite        Opcode = 51
+	OpSpecConstantOp               Opcode = 52
+	OpFunction                     Opcode = 54
+	OpFunctionParameter            Opcode = 55
+	OpFunctionEnd                  Opcode = 56
+	OpFunctionCall                 Opcode = 57
+	OpVariable                     Opcode = 59
+	OpDecorate                     Opcode = 71
+	OpMemberDecorate               Opcode = 72
+	OpDecorationGroup              Opcode = 73
+	OpGroupDecorate                Opcode = 74
+	OpGroupMemberDecorate          Opcode = 75
+	OpLabel                        Opcode = 248
+	OpBranch                       Opcode = 249
+	OpBranchConditional            Opcode = 250
+	OpSwitch                       Opcode = 251
+	OpKill                         Opcode = 252
+	OpReturn                       Opcode = 253
+	OpReturnValue                  Opcode = 254
+	OpUnreachable                  Opcode = 255
+	OpNoLine                       Opcode = 317
+	OpModuleProcessed              Opcode = 330
+	OpExecutionModeId              Opcode = 331
+	OpDecorateId                   Opcode = 332
+	OpTerminateInvocation          Opcode = 4416
+	OpTypeAccelerationStructureKHR Opcode = 5341
+	OpDecorateString               Opcode = 5632
+	OpMemberDecorateString         Opcode = 5633
+)
+
+// Decoration applied to an ID or structure member.
+type Decoration uint32
+
+const (
+	DecorationRelaxedPrecision     Decoration = 0
+	DecorationSpecId               Decoration = 1
+	DecorationBlock                Decoration = 2
+	DecorationBufferBlock          Decoration = 3
+	DecorationRowMajor             Decoration = 4
+	DecorationColMajor             Decoration = 5
+	DecorationArrayStride          Decoration = 6
+	DecorationMatrixStride         Decoration = 7
+	DecorationBuiltIn              Decoration = 11
+	DecorationNoPerspective        Decoration = 13
+	DecorationFlat                 Decoration = 14
+	DecorationPatch                Decoration = 15
+	DecorationCentroid             Decoration = 16
+	DecorationSample               Decoration = 17
+	DecorationNonWritable          Decoration = 24
+	DecorationNonReadable          Decoration = 25
+	DecorationLocation             Decoration = 30
+	DecorationComponent            Decoration = 31
+	DecorationIndex                Decoration = 32
+	DecorationBinding              Decoration = 33
+	DecorationDescriptorSet        Decoration = 34
+	DecorationOffset               Decoration = 35
+	DecorationInputAttachmentIndex Decoration = 43
+)
+
+// BuiltIn value of a [DecorationBuiltIn].
+type BuiltIn uint32
+
+const (
+	BuiltInPosition      BuiltIn = 0
+	BuiltInPointSize     BuiltIn = 1
+	BuiltInClipDistance  BuiltIn = 3
+	BuiltInCullDistance  BuiltIn = 4
+	BuiltInVertexId      BuiltIn = 5
+	BuiltInInstanceId    BuiltIn = 6
+	BuiltInFragCoord     BuiltIn = 15
+	BuiltInFragDepth     BuiltIn = 22
+	BuiltInWorkgroupSize BuiltIn = 25
+	BuiltInVertexIndex   BuiltIn = 42
+	BuiltInInstanceIndex BuiltIn = 43
+)
+
+// StorageClass of a variable or pointer.
+type StorageClass uint32
+
+const (
+	StorageClassUniformConstant StorageClass = 0
+	StorageClassInput           StorageClass = 1
+	StorageClassUniform         StorageClass = 2
+	StorageClassOutput          StorageClass = 3
+	StorageClassWorkgroup       StorageClass = 4
+	StorageClassCrossWorkgroup  StorageClass = 5
+	StorageClassPrivate         StorageClass = 6
+	StorageClassFunction        StorageClass = 7
+	StorageClassGeneric         StorageClass = 8
+	StorageClassPushConstant    StorageClass = 9
+	StorageClassAtomicCounter   StorageClass = 10
+	StorageClassImage           StorageClass = 11
+	StorageClassStorageBuffer   StorageClass = 12
+
+	StorageClassPhysicalStorageBuffer StorageClass = 5349
+)
+
+// ExecutionModel of an [EntryPoint], identifying the shader stage.
+type ExecutionModel uint32
+
+const (
+	ExecutionModelVertex                 ExecutionModel = 0
+	ExecutionModelTessellationControl    ExecutionModel = 1
+	ExecutionModelTessellationEvaluation ExecutionModel = 2
+	ExecutionModelGeometry               ExecutionModel = 3
+	ExecutionModelFragment               ExecutionModel = 4
+	ExecutionModelGLCompute              ExecutionModel = 5
+	ExecutionModelKernel                 ExecutionModel = 6
+)
+
+// String returns the SPIR-V name of the execution model.
+func (model ExecutionModel) String() string {
+	switch model {
+	case ExecutionModelVertex:
+		return "Vertex"
+	case ExecutionModelTessellationControl:
+		return "TessellationControl"
+	case ExecutionModelTessellationEvaluation:
+		return "TessellationEvaluation"
+	case ExecutionModelGeometry:
+		return "Geometry"
+	case ExecutionModelFragment:
+		return "Fragment"
+	case ExecutionModelGLCompute:
+		return "GLCompute"
+	case ExecutionModelKernel:
+		return "Kernel"
+	default:
+		return fmt.Sprintf("ExecutionModel(%d)", uint32(model))
+	}
+}
+
+// ExecutionMode declared for an [EntryPoint].
+type ExecutionMode uint32
+
+const (
+	ExecutionModeOriginUpperLeft ExecutionMode = 7
+	ExecutionModeOriginLowerLeft ExecutionMode = 8
+	ExecutionModeLocalSize       ExecutionMode = 17
+	ExecutionModeLocalSizeHint   ExecutionMode = 18
+	ExecutionModeLocalSizeId     ExecutionMode = 38
+)
+
+// Dim is the dimensionality of an image type.
+type Dim uint32
+
+const (
+	Dim1D          Dim = 0
+	Dim2D          Dim = 1
+	Dim3D          Dim = 2
+	DimCube        Dim = 3
+	DimRect        Dim = 4
+	DimBuffer      Dim = 5
+	DimSubpassData Dim = 6
+)
@@ -0,0 +1,666 @@
+package spirv
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// operandKind within the grammar of an instruction.
+type operandKind int
+
+const (
+	operandResultType     operandKind = iota // <id> of the result type.
+	operandResult                            // <id> of the result.
+	operandID                                // <id> reference.
+	operandLiteral                           // 32-bit literal integer.
+	operandString                            // literal string.
+	operandNumber                            // literal number, with the width of the result type.
+	operandExtInst                           // literal instruction number of an extended instruction set.
+	operandSpecConstantOp                    // literal opcode.
+	operandPairLiteralID                     // literal integer followed by an <id>.
+	operandPairIDLiteral                     // <id> followed by a literal integer.
+	operandPairIDID                          // two <id>s.
+	operandEnum                              // value of an enumerant.
+	operandBitmask                           // bitmask of enumerants.
+	operandDecoration                        // decoration, followed by its parameters.
+	operandExecutionMode                     // execution mode, followed by its parameters.
+)
+
+// operandSpec describes an operand of an instruction.
+type operandSpec struct {
+	kind     operandKind
+	enum     *enum // for operandEnum and operandBitmask
+	quantity byte  // 0 for exactly one, '?' for optional, '*' for any number.
+}
+
+// enum names the values of a SPIR-V operand kind. Bitmask values may be followed by parameters.
+type enum struct {
+	name   string
+	values map[uint32]string
+	params map[uint32]operandKind // parameters that follow a bitmask value.
+	pairs  map[uint32]int         // number of parameters, when more than one.
+}
+
+func (e *enum) String(value uint32) string {
+	if name, ok := e.values[value]; ok {
+		return name
+	}
+	return strconv.FormatUint(uint64(value), 10)
+}
+
+// opInfo describes the grammar of an opcode.
+type opInfo struct {
+	name     string
+	operands []operandSpec
+}
+
+// String returns the SPIR-V name of the opcode.
+func (op Opcode) String() string {
+	if info, ok := grammar[op]; ok {
+		return "Op" + info.name
+	}
+	return fmt.Sprintf("Op(%d)", uint16(op))
+}
+
+func newEnum(name string, values string) *enum {
+	e := &enum{name: name, values: make(map[uint32]string)}
+	for _, field := range strings.Fields(values) {
+		value, name, _ := strings.Cut(field, ":")
+		n, err := strconv.ParseUint(value, 0, 32)
+		if err != nil {
+			panic(err)
+		}
+		e.values[uint32(n)] = name
+	}
+	return e
+}
+
+var (
+	enumSourceLanguage  = newEnum("SourceLanguage", "0:Unknown 1:ESSL 2:GLSL 3:OpenCL_C 4:OpenCL_CPP 5:HLSL 6:CPP_for_OpenCL 7:SYCL 8:HERO_C 9:NZSL 10:WGSL 11:Slang 12:Zig")
+	enumExecutionModel  = newEnum("ExecutionModel", "0:Vertex 1:TessellationControl 2:TessellationEvaluation 3:Geometry 4:Fragment 5:GLCompute 6:Kernel 5267:TaskNV 5268:MeshNV 5313:RayGenerationKHR 5314:IntersectionKHR 5315:AnyHitKHR 5316:ClosestHitKHR 5317:MissKHR 5318:CallableKHR 5364:TaskEXT 5365:MeshEXT")
+	enumAddressingModel = newEnum("AddressingModel", "0:Logical 1:Physical32 2:Physical64 5348:PhysicalStorageBuffer64")
+	enumMemoryModel     = newEnum("MemoryModel", "0:Simple 1:GLSL450 2:OpenCL 3:Vulkan")
+	enumExecutionMode   = newEnum("ExecutionMode", "0:Invocations 1:SpacingEqual 2:SpacingFractionalEven 3:SpacingFractionalOdd 4:VertexOrderCw 5:VertexOrderCcw 6:PixelCenterInteger 7:OriginUpperLeft 8:OriginLowerLeft 9:EarlyFragmentTests 10:PointMode 11:Xfb 12:DepthReplacing 14:DepthGreater 15:DepthLess 16:DepthUnchanged 17:LocalSize 18:LocalSizeHint 19:InputPoints 20:InputLines 21:InputLinesAdjacency 22:Triangles 23:InputTrianglesAdjacency 24:Quads 25:Isolines 26:OutputVertices 27:OutputPoints 28:OutputLineStrip 29:OutputTriangleStrip 30:VecTypeHint 31:ContractionOff 33:Initializer 34:Finalizer 35:SubgroupSize 36:SubgroupsPerWorkgroup 37:SubgroupsPerWorkgroupId 38:LocalSizeId 39:LocalSizeHintId 4421:SubgroupUniformControlFlowKHR 4446:PostDepthCoverage 4459:DenormPreserve 4460:DenormFlushToZero 4461:SignedZeroInfNanPreserve 4462:RoundingModeRTE 4463:RoundingModeRTZ 5027:StencilRefReplacingEXT 5269:OutputLinesNV 5270:OutputPrimitivesNV 5289:DerivativeGroupQuadsNV 5290:DerivativeGroupLinearNV 5298:OutputTrianglesNV 5366:PixelInterlockOrderedEXT 5367:PixelInterlockUnorderedEXT 5368:SampleInterlockOrderedEXT 5369:SampleInterlockUnorderedEXT 5370:ShadingRateInterlockOrderedEXT 5371:ShadingRateInterlockUnorderedEXT")
+	enumStorageClass    = newEnum("StorageClass", "0:UniformConstant 1:Input 2:Uniform 3:Output 4:Workgroup 5:CrossWorkgroup 6:Private 7:Function 8:Generic 9:PushConstant 10:AtomicCounter 11:Image 12:StorageBuffer 5328:CallableDataKHR 5329:IncomingCallableDataKHR 5338:RayPayloadKHR 5339:HitAttributeKHR 5342:IncomingRayPayloadKHR 5343:ShaderRecordBufferKHR 5349:PhysicalStorageBuffer 5402:TaskPayloadWorkgroupEXT")
+	enumDim             = newEnum("Dim", "0:1D 1:2D 2:3D 3:Cube 4:Rect 5:Buffer 6:SubpassData")
+	enumSamplerAddress  = newEnum("SamplerAddressingMode", "0:None 1:ClampToEdge 2:Clamp 3:Repeat 4:RepeatMirrored")
+	enumSamplerFilter   = newEnum("SamplerFilterMode", "0:Nearest 1:Linear")
+	enumImageFormat     = newEnum("ImageFormat", "0:Unknown 1:Rgba32f 2:Rgba16f 3:R32f 4:Rgba8 5:Rgba8Snorm 6:Rg32f 7:Rg16f 8:R11fG11fB10f 9:R16f 10:Rgba16 11:Rgb10A2 12:Rg16 13:Rg8 14:R16 15:R8 16:Rgba16Snorm 17:Rg16Snorm 18:Rg8Snorm 19:R16Snorm 20:R8Snorm 21:Rgba32i 22:Rgba16i 23:Rgba8i 24:R32i 25:Rg32i 26:Rg16i 27:Rg8i 28:R16i 29:R8i 30:Rgba32ui 31:Rgba16ui 32:Rgba8ui 33:R32ui 34:Rgb10a2ui 35:Rg32ui 36:Rg16ui 37:Rg8ui 38:R16ui 39:R8ui 40:R64ui 41:R64i")
+	enumAccessQualifier = newEnum("AccessQualifier", "0:ReadOnly 1:WriteOnly 2:ReadWrite")
+	enumGroupOperation  = newEnum("GroupOperation", "0:Reduce 1:InclusiveScan 2:ExclusiveScan 3:ClusteredReduce")
+	enumLinkageType     = newEnum("LinkageType", "0:Export 1:Import 2:LinkOnceODR")
+	enumFPRoundingMode  = newEnum("FPRoundingMode", "0:RTE 1:RTZ 2:RTP 3:RTN")
+	enumFuncParamAttr   = newEnum("FunctionParameterAttribute", "0:Zext 1:Sext 2:ByVal 3:Sret 4:NoAlias 5:NoCapture 6:NoWrite 7:NoReadWrite")
+	enumBuiltIn         = newEnum("BuiltIn", "0:Position 1:PointSize 3:ClipDistance 4:CullDistance 5:VertexId 6:InstanceId 7:PrimitiveId 8:InvocationId 9:Layer 10:ViewportIndex 11:TessLevelOuter 12:TessLevelInner 13:TessCoord 14:PatchVertices 15:FragCoord 16:PointCoord 17:FrontFacing 18:SampleId 19:SamplePosition 20:SampleMask 22:FragDepth 23:HelperInvocation 24:NumWorkgroups 25:WorkgroupSize 26:WorkgroupId 27:LocalInvocationId 28:GlobalInvocationId 29:LocalInvocationIndex 30:WorkDim 31:GlobalSize 32:EnqueuedWorkgroupSize 33:GlobalOffset 34:GlobalLinearId 36:SubgroupSize 37:SubgroupMaxSize 38:NumSubgroups 39:NumEnqueuedSubgroups 40:SubgroupId 41:SubgroupLocalInvocationId 42:VertexIndex 43:InstanceIndex 4416:SubgroupEqMask 4417:SubgroupGeMask 4418:SubgroupGtMask 4419:SubgroupLeMask 4420:SubgroupLtMask 4424:BaseVertex 4425:BaseInstance 4426:DrawIndex 4432:PrimitiveShadingRateKHR 4438:DeviceIndex 4440:ViewIndex 4444:ShadingRateKHR 5014:FragStencilRefEXT 5253:ViewportMaskNV 5264:FullyCoveredEXT 5286:BaryCoordKHR 5287:BaryCoordNoPerspKHR 5292:FragSizeEXT 5293:FragInvocationCountEXT 5319:LaunchIdKHR 5320:LaunchSizeKHR 5321:WorldRayOriginKHR 5322:WorldRayDirectionKHR 5323:ObjectRayOriginKHR 5324:ObjectRayDirectionKHR 5325:RayTminKHR 5326:RayTmaxKHR 5327:InstanceCustomIndexKHR 5330:ObjectToWorldKHR 5331:WorldToObjectKHR 5333:HitKindKHR 5351:IncomingRayFlagsKHR 5352:RayGeometryIndexKHR 5374:WarpsPerSMNV 5375:SMCountNV 5376:WarpIDNV 5377:SMIDNV 6021:CullMaskKHR")
+	enumDecoration      = newEnum("Decoration", "0:RelaxedPrecision 1:SpecId 2:Block 3:BufferBlock 4:RowMajor 5:ColMajor 6:ArrayStride 7:MatrixStride 8:GLSLShared 9:GLSLPacked 10:CPacked 11:BuiltIn 13:NoPerspective 14:Flat 15:Patch 16:Centroid 17:Sample 18:Invariant 19:Restrict 20:Aliased 21:Volatile 22:Constant 23:Coherent 24:NonWritable 25:NonReadable 26:Uniform 27:UniformId 28:SaturatedConversion 29:Stream 30:Location 31:Component 32:Index 33:Binding 34:DescriptorSet 35:Offset 36:XfbBuffer 37:XfbStride 38:FuncParamAttr 39:FPRoundingMode 40:FPFastMathMode 41:LinkageAttributes 42:NoContraction 43:InputAttachmentIndex 44:Alignment 45:MaxByteOffset 46:AlignmentId 47:MaxByteOffsetId 4469:NoSignedWrap 4470:NoUnsignedWrap 4487:WeightTextureQCOM 4488:BlockMatchTextureQCOM 4999:ExplicitInterpAMD 5248:OverrideCoverageNV 5250:PassthroughNV 5252:ViewportRelativeNV 5256:SecondaryViewportRelativeNV 5271:PerPrimitiveEXT 5272:PerViewNV 5273:PerTaskNV 5285:PerVertexKHR 5300:NonUniform 5355:RestrictPointer 5356:AliasedPointer 5386:BindlessSamplerNV 5387:BindlessImageNV 5388:BoundSamplerNV 5389:BoundImageNV 5634:CounterBuffer 5635:UserSemantic 5636:UserTypeGOOGLE")
+	enumCapability      = newEnum("Capability", "0:Matrix 1:Shader 2:Geometry 3:Tessellation 4:Addresses 5:Linkage 6:Kernel 7:Vector16 8:Float16Buffer 9:Float16 10:Float64 11:Int64 12:Int64Atomics 13:ImageBasic 14:ImageReadWrite 15:ImageMipmap 17:Pipes 18:Groups 19:DeviceEnqueue 20:LiteralSampler 21:AtomicStorage 22:Int16 23:TessellationPointSize 24:GeometryPointSize 25:ImageGatherExtended 27:StorageImageMultisample 28:UniformBufferArrayDynamicIndexing 29:SampledImageArrayDynamicIndexing 30:StorageBufferArrayDynamicIndexing 31:StorageImageArrayDynamicIndexing 32:ClipDistance 33:CullDistance 34:ImageCubeArray 35:SampleRateShading 36:ImageRect 37:SampledRect 38:GenericPointer 39:Int8 40:InputAttachment 41:SparseResidency 42:MinLod 43:Sampled1D 44:Image1D 45:SampledCubeArray 46:SampledBuffer 47:ImageBuffer 48:ImageMSArray 49:StorageImageExtendedFormats 50:ImageQuery 51:DerivativeControl 52:InterpolationFunction 53:TransformFeedback 54:GeometryStreams 55:StorageImageReadWithoutFormat 56:StorageImageWriteWithoutFormat 57:MultiViewport 58:SubgroupDispatch 59:NamedBarrier 60:PipeStorage 61:GroupNonUniform 62:GroupNonUniformVote 63:GroupNonUniformArithmetic 64:GroupNonUniformBallot 65:GroupNonUniformShuffle 66:GroupNonUniformShuffleRelative 67:GroupNonUniformClustered 68:GroupNonUniformQuad 69:ShaderLayer 70:ShaderViewportIndex 71:UniformDecoration 4422:FragmentShadingRateKHR 4423:SubgroupBallotKHR 4427:DrawParameters 4428:WorkgroupMemoryExplicitLayoutKHR 4429:WorkgroupMemoryExplicitLayout8BitAccessKHR 4430:WorkgroupMemoryExplicitLayout16BitAccessKHR 4431:SubgroupVoteKHR 4433:StorageBuffer16BitAccess 4434:UniformAndStorageBuffer16BitAccess 4435:StoragePushConstant16 4436:StorageInputOutput16 4437:DeviceGroup 4439:MultiView 4441:VariablePointersStorageBuffer 4442:VariablePointers 4445:AtomicStorageOps 4447:SampleMaskPostDepthCoverage 4448:StorageBuffer8BitAccess 4449:UniformAndStorageBuffer8BitAccess 4450:StoragePushConstant8 4464:DenormPreserve 4465:DenormFlushToZero 4466:SignedZeroInfNanPreserve 4467:RoundingModeRTE 4468:RoundingModeRTZ 4471:RayQueryProvisionalKHR 4472:RayQueryKHR 4478:RayTraversalPrimitiveCullingKHR 4479:RayTracingKHR 5008:Float16ImageAMD 5009:ImageGatherBiasLodAMD 5010:FragmentMaskAMD 5013:StencilExportEXT 5015:ImageReadWriteLodAMD 5016:Int64ImageEXT 5055:ShaderClockKHR 5249:SampleMaskOverrideCoverageNV 5251:GeometryShaderPassthroughNV 5254:ShaderViewportIndexLayerEXT 5255:ShaderViewportMaskNV 5259:ShaderStereoViewNV 5260:PerViewAttributesNV 5265:FragmentFullyCoveredEXT 5266:MeshShadingNV 5282:ImageFootprintNV 5283:MeshShadingEXT 5284:FragmentBarycentricKHR 5288:ComputeDerivativeGroupQuadsNV 5291:FragmentDensityEXT 5297:GroupNonUniformPartitionedNV 5301:ShaderNonUniform 5302:RuntimeDescriptorArray 5303:InputAttachmentArrayDynamicIndexing 5304:UniformTexelBufferArrayDynamicIndexing 5305:StorageTexelBufferArrayDynamicIndexing 5306:UniformBufferArrayNonUniformIndexing 5307:SampledImageArrayNonUniformIndexing 5308:StorageBufferArrayNonUniformIndexing 5309:StorageImageArrayNonUniformIndexing 5310:InputAttachmentArrayNonUniformIndexing 5311:UniformTexelBufferArrayNonUniformIndexing 5312:StorageTexelBufferArrayNonUniformIndexing 5340:RayTracingNV 5345:VulkanMemoryModel 5346:VulkanMemoryModelDeviceScope 5347:PhysicalStorageBufferAddresses 5350:ComputeDerivativeGroupLinearNV 5353:RayTracingProvisionalKHR 5357:CooperativeMatrixNV 5363:FragmentShaderSampleInterlockEXT 5372:FragmentShaderShadingRateInterlockEXT 5373:ShaderSMBuiltinsNV 5378:FragmentShaderPixelInterlockEXT 5379:DemoteToHelperInvocation 5381:RayTracingOpacityMicromapEXT 5390:BindlessTextureNV 6016:AtomicFloat32AddEXT 6017:AtomicFloat64AddEXT 6033:AtomicFloat16AddEXT 6095:AtomicFloat16MinMaxEXT 6096:AtomicFloat32MinMaxEXT 6097:AtomicFloat64MinMaxEXT")
+
+	maskFunctionControl  = newEnum("FunctionControl", "0x1:Inline 0x2:DontInline 0x4:Pure 0x8:Const")
+	maskSelectionControl = newEnum("SelectionControl", "0x1:Flatten 0x2:DontFlatten")
+	maskFPFastMath       = newEnum("FPFastMathMode", "0x1:NotNaN 0x2:NotInf 0x4:NSZ 0x8:AllowRecip 0x10:Fast")
+	maskLoopControl      = newEnum("LoopControl", "0x1:Unroll 0x2:DontUnroll 0x4:DependencyInfinite 0x8:DependencyLength 0x10:MinIterations 0x20:MaxIterations 0x40:IterationMultiple 0x80:PeelCount 0x100:PartialCount")
+	maskMemoryAccess     = newEnum("MemoryAccess", "0x1:Volatile 0x2:Aligned 0x4:Nontemporal 0x8:MakePointerAvailable 0x10:MakePointerVisible 0x20:NonPrivatePointer")
+	maskImageOperands    = newEnum("ImageOperands", "0x1:Bias 0x2:Lod 0x4:Grad 0x8:ConstOffset 0x10:Offset 0x20:ConstOffsets 0x40:Sample 0x80:MinLod 0x100:MakeTexelAvailable 0x200:MakeTexelVisible 0x400:NonPrivateTexel 0x800:VolatileTexel 0x1000:SignExtend 0x2000:ZeroExtend 0x4000:Nontemporal 0x10000:Offsets")
+)
+
+func init() {
+	maskLoopControl.params = map[uint32]operandKind{0x8: operandLiteral, 0x10: operandLiteral, 0x20: operandLiteral, 0x40: operandLiteral, 0x80: operandLiteral, 0x100: operandLiteral}
+	maskMemoryAccess.params = map[uint32]operandKind{0x2: operandLiteral, 0x8: operandID, 0x10: operandID}
+	maskImageOperands.params = map[uint32]operandKind{0x1: operandID, 0x2: operandID, 0x4: operandID, 0x8: operandID, 0x10: operandID, 0x20: operandID, 0x40: operandID, 0x80: operandID, 0x100: operandID, 0x200: operandID, 0x10000: operandID}
+	maskImageOperands.pairs = map[uint32]int{0x4: 2}
+}
+
+var enums = map[string]*enum{
+	"SourceLanguage":        enumSourceLanguage,
+	"ExecutionModel":        enumExecutionModel,
+	"AddressingModel":       enumAddressingModel,
+	"MemoryModel":           enumMemoryModel,
+	"StorageClass":          enumStorageClass,
+	"Dim":                   enumDim,
+	"SamplerAddressingMode": enumSamplerAddress,
+	"SamplerFilterMode":     enumSamplerFilter,
+	"ImageFormat":           enumImageFormat,
+	"AccessQualifier":       enumAccessQualifier,
+	"GroupOperation":        enumGroupOperation,
+	"Capability":            enumCapability,
+}
+
+var masks = map[string]*enum{
+	"FunctionControl":  maskFunctionControl,
+	"SelectionControl": maskSelectionControl,
+	"LoopControl":      maskLoopControl,
+	"MemoryAccess":     maskMemoryAccess,
+	"ImageOperands":    maskImageOperands,
+}
+
+/*
+grammarSource lists each opcode of the unified SPIR-V core grammar that is relevant to shaders, along with
+its operands, where:
+
+	T = <id> Result Type, R = <id> Result, I = <id>, L = literal integer, S = literal string,
+	N = context-dependent literal number, X = extended instruction, P = spec constant opcode,
+	LI, IL and II = pairs of literals and <id>s, D = decoration, E = execution mode.
+
+Other words name an enum (or bitmask) and each operand may be followed by a '?' (optional) or '*' (any number).
+*/
+const grammarSource = `
+0 Nop
+1 Undef T R
+2 SourceContinued S
+3 Source SourceLanguage L I? S?
+4 SourceExtension S
+5 Name I S
+6 MemberName I L S
+7 String R S
+8 Line I L L
+10 Extension S
+11 ExtInstImport R S
+12 ExtInst T R I X I*
+14 MemoryModel AddressingModel MemoryModel
+15 EntryPoint ExecutionModel I S I*
+16 ExecutionMode I E
+17 Capability Capability
+19 TypeVoid R
+20 TypeBool R
+21 TypeInt R L L
+22 TypeFloat R L L?
+23 TypeVector R I L
+24 TypeMatrix R I L
+25 TypeImage R I Dim L L L L ImageFormat AccessQualifier?
+26 TypeSampler R
+27 TypeSampledImage R I
+28 TypeArray R I I
+29 TypeRuntimeArray R I
+30 TypeStruct R I*
+31 TypeOpaque R S
+32 TypePointer R StorageClass I
+33 TypeFunction R I I*
+34 TypeEvent R
+35 TypeDeviceEvent R
+36 TypeReserveId R
+37 TypeQueue R
+38 TypePipe R AccessQualifier
+39 TypeForwardPointer I StorageClass
+41 ConstantTrue T R
+42 ConstantFalse T R
+43 Constant T R N
+44 ConstantComposite T R I*
+45 ConstantSampler T R SamplerAddressingMode L SamplerFilterMode
+46 ConstantNull T R
+48 SpecConstantTrue T R
+49 SpecConstantFalse T R
+50 SpecConstant T R N
+51 SpecConstantComposite T R I*
+52 SpecConstantOp T R P I*
+54 Function T R FunctionControl I
+55 FunctionParameter T R
+56 FunctionEnd
+57 FunctionCall T R I I*
+59 Variable T R StorageClass I?
+60 ImageTexelPointer T R I I I
+61 Load T R I MemoryAccess?
+62 Store I I MemoryAccess?
+63 CopyMemory I I MemoryAccess? MemoryAccess?
+64 CopyMemorySized I I I MemoryAccess? MemoryAccess?
+65 AccessChain T R I I*
+66 InBoundsAccessChain T R I I*
+67 PtrAccessChain T R I I I*
+68 ArrayLength T R I L
+69 GenericPtrMemSemantics T R I
+70 InBoundsPtrAccessChain T R I I I*
+71 Decorate I D
+72 MemberDecorate I L D
+73 DecorationGroup R
+74 GroupDecorate I I*
+75 GroupMemberDecorate I IL*
+77 VectorExtractDynamic T R I I
+78 VectorInsertDynamic T R I I I
+79 VectorShuffle T R I I L*
+80 CompositeConstruct T R I*
+81 CompositeExtract T R I L*
+82 CompositeInsert T R I I L*
+83 CopyObject T R I
+84 Transpose T R I
+86 SampledImage T R I I
+87 ImageSampleImplicitLod T R I I ImageOperands?
+88 ImageSampleExplicitLod T R I I ImageOperands
+89 ImageSampleDrefImplicitLod T R I I I ImageOperands?
+90 ImageSampleDrefExplicitLod T R I I I ImageOperands
+91 ImageSampleProjImplicitLod T R I I ImageOperands?
+92 ImageSampleProjExplicitLod T R I I ImageOperands
+93 ImageSampleProjDrefImplicitLod T R I I I ImageOperands?
+94 ImageSampleProjDrefExplicitLod T R I I I ImageOperands
+95 ImageFetch T R I I ImageOperands?
+96 ImageGather T R I I I ImageOperands?
+97 ImageDrefGather T R I I I ImageOperands?
+98 ImageRead T R I I ImageOperands?
+99 ImageWrite I I I ImageOperands?
+100 Image T R I
+101 ImageQueryFormat T R I
+102 ImageQueryOrder T R I
+103 ImageQuerySizeLod T R I I
+104 ImageQuerySize T R I
+105 ImageQueryLod T R I I
+106 ImageQueryLevels T R I
+107 ImageQuerySamples T R I
+109 ConvertFToU T R I
+110 ConvertFToS T R I
+111 ConvertSToF T R I
+112 ConvertUToF T R I
+113 UConvert T R I
+114 SConvert T R I
+115 FConvert T R I
+116 QuantizeToF16 T R I
+117 ConvertPtrToU T R I
+118 SatConvertSToU T R I
+119 SatConvertUToS T R I
+120 ConvertUToPtr T R I
+121 PtrCastToGeneric T R I
+122 GenericCastToPtr T R I
+123 GenericCastToPtrExplicit T R I StorageClass
+124 Bitcast T R I
+126 SNegate T R I
+127 FNegate T R I
+128 IAdd T R I I
+129 FAdd T R I I
+130 ISub T R I I
+131 FSub T R I I
+132 IMul T R I I
+133 FMul T R I I
+134 UDiv T R I I
+135 SDiv T R I I
+136 FDiv T R I I
+137 UMod T R I I
+138 SRem T R I I
+139 SMod T R I I
+140 FRem T R I I
+141 FMod T R I I
+142 VectorTimesScalar T R I I
+143 MatrixTimesScalar T R I I
+144 VectorTimesMatrix T R I I
+145 MatrixTimesVector T R I I
+146 MatrixTimesMatrix T R I I
+147 OuterProduct T R I I
+148 Dot T R I I
+149 IAddCarry T R I I
+150 ISubBorrow T R I I
+151 UMulExtended T R I I
+152 SMulExtended T R I I
+154 Any T R I
+155 All T R I
+156 IsNan T R I
+157 IsInf T R I
+158 IsFinite T R I
+159 IsNormal T R I
+160 SignBitSet T R I
+161 LessOrGreater T R I I
+162 Ordered T R I I
+163 Unordered T R I I
+164 LogicalEqual T R I I
+165 LogicalNotEqual T R I I
+166 LogicalOr T R I I
+167 LogicalAnd T R I I
+168 LogicalNot T R I
+169 Select T R I I I
+170 IEqual T R I I
+171 INotEqual T R I I
+172 UGreaterThan T R I I
+173 SGreaterThan T R I I
+174 UGreaterThanEqual T R I I
+175 SGreaterThanEqual T R I I
+176 ULessThan T R I I
+177 SLessThan T R I I
+178 ULessThanEqual T R I I
+179 SLessThanEqual T R I I
+180 FOrdEqual T R I I
+181 FUnordEqual T R I I
+182 FOrdNotEqual T R I I
+183 FUnordNotEqual T R I I
+184 FOrdLessThan T R I I
+185 FUnordLessThan T R I I
+186 FOrdGreaterThan T R I I
+187 FUnordGreaterThan T R I I
+188 FOrdLessThanEqual T R I I
+189 FUnordLessThanEqual T R I I
+190 FOrdGreaterThanEqual T R I I
+191 FUnordGreaterThanEqual T R I I
+194 ShiftRightLogical T R I I
+195 ShiftRightArithmetic T R I I
+196 ShiftLeftLogical T R I I
+197 BitwiseOr T R I I
+198 BitwiseXor T R I I
+199 BitwiseAnd T R I I
+200 Not T R I
+201 BitFieldInsert T R I I I I
+202 BitFieldSExtract T R I I I
+203 BitFieldUExtract T R I I I
+204 BitReverse T R I
+205 BitCount T R I
+207 DPdx T R I
+208 DPdy T R I
+209 Fwidth T R I
+210 DPdxFine T R I
+211 DPdyFine T R I
+212 FwidthFine T R I
+213 DPdxCoarse T R I
+214 DPdyCoarse T R I
+215 FwidthCoarse T R I
+218 EmitVertex
+219 EndPrimitive
+220 EmitStreamVertex I
+221 EndStreamPrimitive I
+224 ControlBarrier I I I
+225 MemoryBarrier I I
+227 AtomicLoad T R I I I
+228 AtomicStore I I I I
+229 AtomicExchange T R I I I I
+230 AtomicCompareExchange T R I I I I I I
+231 AtomicCompareExchangeWeak T R I I I I I I
+232 AtomicIIncrement T R I I I
+233 AtomicIDecrement T R I I I
+234 AtomicIAdd T R I I I I
+235 AtomicISub T R I I I I
+236 AtomicSMin T R I I I I
+237 AtomicUMin T R I I I I
+238 AtomicSMax T R I I I I
+239 AtomicUMax T R I I I I
+240 AtomicAnd T R I I I I
+241 AtomicOr T R I I I I
+242 AtomicXor T R I I I I
+245 Phi T R II*
+246 LoopMerge I I LoopControl
+247 SelectionMerge I SelectionControl
+248 Label R
+249 Branch I
+250 BranchConditional I I I L*
+251 Switch I I LI*
+252 Kill
+253 Return
+254 ReturnValue I
+255 Unreachable
+256 LifetimeStart I L
+257 LifetimeStop I L
+261 GroupAll T R I I
+262 GroupAny T R I I
+263 GroupBroadcast T R I I I
+264 GroupIAdd T R I GroupOperation I
+265 GroupFAdd T R I GroupOperation I
+266 GroupFMin T R I GroupOperation I
+267 GroupUMin T R I GroupOperation I
+268 GroupSMin T R I GroupOperation I
+269 GroupFMax T R I GroupOperation I
+270 GroupUMax T R I GroupOperation I
+271 GroupSMax T R I GroupOperation I
+305 ImageSparseSampleImplicitLod T R I I ImageOperands?
+306 ImageSparseSampleExplicitLod T R I I ImageOperands
+307 ImageSparseSampleDrefImplicitLod T R I I I ImageOperands?
+308 ImageSparseSampleDrefExplicitLod T R I I I ImageOperands
+313 ImageSparseFetch T R I I ImageOperands?
+314 ImageSparseGather T R I I I ImageOperands?
+315 ImageSparseDrefGather T R I I I ImageOperands?
+316 ImageSparseTexelsResident T R I
+317 NoLine
+320 ImageSparseRead T R I I ImageOperands?
+330 ModuleProcessed S
+331 ExecutionModeId I E
+332 DecorateId I D
+333 GroupNonUniformElect T R I
+334 GroupNonUniformAll T R I I
+335 GroupNonUniformAny T R I I
+336 GroupNonUniformAllEqual T R I I
+337 GroupNonUniformBroadcast T R I I I
+338 GroupNonUniformBroadcastFirst T R I I
+339 GroupNonUniformBallot T R I I
+340 GroupNonUniformInverseBallot T R I I
+341 GroupNonUniformBallotBitExtract T R I I I
+342 GroupNonUniformBallotBitCount T R I GroupOperation I
+343 GroupNonUniformBallotFindLSB T R I I
+344 GroupNonUniformBallotFindMSB T R I I
+345 GroupNonUniformShuffle T R I I I
+346 GroupNonUniformShuffleXor T R I I I
+347 GroupNonUniformShuffleUp T R I I I
+348 GroupNonUniformShuffleDown T R I I I
+349 GroupNonUniformIAdd T R I GroupOperation I I?
+350 GroupNonUniformFAdd T R I GroupOperation I I?
+351 GroupNonUniformIMul T R I GroupOperation I I?
+352 GroupNonUniformFMul T R I GroupOperation I I?
+353 GroupNonUniformSMin T R I GroupOperation I I?
+354 GroupNonUniformUMin T R I GroupOperation I I?
+355 GroupNonUniformFMin T R I GroupOperation I I?
+356 GroupNonUniformSMax T R I GroupOperation I I?
+357 GroupNonUniformUMax T R I GroupOperation I I?
+358 GroupNonUniformFMax T R I GroupOperation I I?
+359 GroupNonUniformBitwiseAnd T R I GroupOperation I I?
+360 GroupNonUniformBitwiseOr T R I GroupOperation I I?
+361 GroupNonUniformBitwiseXor T R I GroupOperation I I?
+362 GroupNonUniformLogicalAnd T R I GroupOperation I I?
+363 GroupNonUniformLogicalOr T R I GroupOperation I I?
+364 GroupNonUniformLogicalXor T R I GroupOperation I I?
+365 GroupNonUniformQuadBroadcast T R I I I
+366 GroupNonUniformQuadSwap T R I I I
+400 CopyLogical T R I
+401 PtrEqual T R I I
+402 PtrNotEqual T R I I
+403 PtrDiff T R I I
+4416 TerminateInvocation
+4421 SubgroupBallotKHR T R I
+4422 SubgroupFirstInvocationKHR T R I
+4428 SubgroupAllKHR T R I
+4429 SubgroupAnyKHR T R I
+4430 SubgroupAllEqualKHR T R I
+4432 SubgroupReadInvocationKHR T R I I
+5341 TypeAccelerationStructureKHR R
+5380 DemoteToHelperInvocation
+5381 IsHelperInvocationEXT T R
+5632 DecorateString I D
+5633 MemberDecorateString I L D
+`
+
+var grammar = parseGrammar(grammarSource)
+
+func parseGrammar(source string) map[Opcode]*opInfo {
+	codes := map[string]operandKind{
+		"T": operandResultType, "R": operandResult, "I": operandID, "L": operandLiteral,
+		"S": operandString, "N": operandNumber, "X": operandExtInst, "P": operandSpecConstantOp,
+		"LI": operandPairLiteralID, "IL": operandPairIDLiteral, "II": operandPairIDID,
+		"D": operandDecoration, "E": operandExecutionMode,
+	}
+	table := make(map[Opcode]*opInfo)
+	for _, line := range strings.Split(strings.TrimSpace(source), "\n") {
+		fields := strings.Fields(line)
+		opcode, err := strconv.Atoi(fields[0])
+		if err != nil {
+			panic(err)
+		}
+		info := &opInfo{name: fields[1]}
+		for _, field := range fields[2:] {
+			var spec operandSpec
+			if last := field[len(field)-1]; last == '?' || last == '*' {
+				spec.quantity, field = last, field[:len(field)-1]
+			}
+			if kind, ok := codes[field]; ok {
+				spec.kind = kind
+			} else if e, ok := enums[field]; ok {
+				spec.kind, spec.enum = operandEnum, e
+			} else if e, ok := masks[field]; ok {
+				spec.kind, spec.enum = operandBitmask, e
+			} else {
+				panic("spirv: unknown operand kind " + field)
+			}
+			info.operands = append(info.operands, spec)
+		}
+		table[Opcode(opcode)] = info
+	}
+	return table
+}
+
+// operand of a decoded instruction.
+type operand struct {
+	kind  operandKind
+	enum  *enum
+	words []uint32
+}
+
+/*
+decode the operands of the instruction according to the grammar. The width (in words) of any literal
+numbers used in a switch is determined by calling selectorWidth with the selector <id>. Operands of
+unknown instructions are decoded as literals.
+*/
+func (inst Instruction) decode(selectorWidth func(id uint32) int) ([]operand, error) {
+	info, ok := grammar[inst.Opcode]
+	if !ok {
+		operands := make([]operand, len(inst.Operands))
+		for i := range inst.Operands {
+			operands[i] = operand{kind: operandLiteral, words: inst.Operands[i : i+1]}
+		}
+		return operands, nil
+	}
+	var (
+		operands []operand
+		words    = inst.Operands
+	)
+	take := func(kind operandKind, e *enum, n int) error {
+		if n > len(words) {
+			return fmt.Errorf("spirv: %v is missing operands", inst.Opcode)
+		}
+		operands = append(operands, operand{kind: kind, enum: e, words: words[:n:n]})
+		words = words[n:]
+		return nil
+	}
+	for _, spec := range info.operands {
+		for repeat := true; repeat; repeat = spec.quantity == '*' {
+			if spec.quantity != 0 && len(words) == 0 {
+				break
+			}
+			var err error
+			switch spec.kind {
+			case operandString:
+				_, n := String(words)
+				err = take(operandString, nil, n)
+			case operandNumber:
+				err = take(operandNumber, nil, len(words))
+			case operandPairLiteralID:
+				width := 1
+				if selectorWidth != nil && len(inst.Operands) > 0 {
+					width = selectorWidth(inst.Operands[0])
+				}
+				if err = take(operandLiteral, nil, width); err == nil {
+					err = take(operandID, nil, 1)
+				}
+			case operandPairIDLiteral:
+				if err = take(operandID, nil, 1); err == nil {
+					err = take(operandLiteral, nil, 1)
+				}
+			case operandPairIDID:
+				if err = take(operandID, nil, 1); err == nil {
+					err = take(operandID, nil, 1)
+				}
+			case operandBitmask:
+				if err = take(operandBitmask, spec.enum, 1); err != nil {
+					break
+				}
+				mask := operands[len(operands)-1].words[0]
+				for bit := uint32(1); bit != 0 && err == nil; bit <<= 1 {
+					if kind, ok := spec.enum.params[bit]; ok && mask&bit != 0 {
+						for i := max(1, spec.enum.pairs[bit]); i > 0 && err == nil; i-- {
+							err = take(kind, nil, 1)
+						}
+					}
+				}
+			case operandDecoration:
+				if err = take(operandEnum, enumDecoration, 1); err != nil {
+					break
+				}
+				err = inst.decodeDecoration(Decoration(operands[len(operands)-1].words[0]), &words, &operands)
+			case operandSpecConstantOp:
+				if err = take(operandSpecConstantOp, nil, 1); err != nil {
+					break
+				}
+				err = decodeSpecConstantOp(Opcode(operands[len(operands)-1].words[0]), &words, &operands)
+			case operandExecutionMode:
+				if err = take(operandEnum, enumExecutionMode, 1); err != nil {
+					break
+				}
+				kind := operandLiteral
+				if inst.Opcode == OpExecutionModeId {
+					kind = operandID
+				}
+				for len(words) > 0 && err == nil {
+					err = take(kind, nil, 1)
+				}
+			default:
+				err = take(spec.kind, spec.enum, 1)
+			}
+			if err != nil {
+				return nil, err
+			}
+			if len(words) == 0 {
+				break
+			}
+		}
+	}
+	for len(words) > 0 {
+		operands = append(operands, operand{kind: operandLiteral, words: words[:1:1]})
+		words = words[1:]
+	}
+	return operands, nil
+}
+
+// decodeSpecConstantOp decodes the operands of the opcode embedded in an OpSpecConstantOp, which follow the
+// grammar of that opcode without its result type and result, such that literal indices are not taken for <id>s.
+func decodeSpecConstantOp(opcode Opcode, words *[]uint32, operands *[]operand) error {
+	info, ok := grammar[opcode]
+	if !ok || len(info.operands) < 2 || info.operands[0].kind != operandResultType || info.operands[1].kind != operandResult {
+		return fmt.Errorf("spirv: %v cannot be used by OpSpecConstantOp", opcode)
+	}
+	embedded, err := Instruction{Opcode: opcode, Operands: append([]uint32{0, 0}, *words...)}.decode(nil)
+	if err != nil {
+		return err
+	}
+	offset := 0
+	for _, op := range embedded[2:] {
+		n := len(op.words)
+		op.words = (*words)[offset : offset+n : offset+n]
+		*operands = append(*operands, op)
+		offset += n
+	}
+	*words = (*words)[offset:]
+	return nil
+}
+
+// decodeDecoration decodes the parameters of a decoration.
+func (inst Instruction) decodeDecoration(decoration Decoration, words *[]uint32, operands *[]operand) error {
+	take := func(kind operandKind, e *enum, n int) {
+		n = min(n, len(*words))
+		*operands = append(*operands, operand{kind: kind, enum: e, words: (*words)[:n:n]})
+		*words = (*words)[n:]
+	}
+	switch {
+	case inst.Opcode == OpDecorateString || inst.Opcode == OpMemberDecorateString:
+		for len(*words) > 0 {
+			_, n := String(*words)
+			take(operandString, nil, n)
+		}
+	case inst.Opcode == OpDecorateId:
+		for len(*words) > 0 {
+			take(operandID, nil, 1)
+		}
+	case decoration == DecorationBuiltIn:
+		take(operandEnum, enumBuiltIn, 1)
+	case decoration == 38: // FuncParamAttr
+		take(operandEnum, enumFuncParamAttr, 1)
+	case decoration == 39: // FPRoundingMode
+		take(operandEnum, enumFPRoundingMode, 1)
+	case decoration == 40: // FPFastMathMode
+		take(operandBitmask, maskFPFastMath, 1)
+	case decoration == 41: // LinkageAttributes
+		_, n := String(*words)
+		take(operandString, nil, n)
+		take(operandEnum, enumLinkageType, 1)
+	}
+	for len(*words) > 0 {
+		take(operandLiteral, nil, 1)
+	}
+	return nil
+}
@@ -0,0 +1,423 @@
+package spirv
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+
+	"grow.graphics/rd"
+)
+
+/*
+Pass transforms a module in place. Passes copy the operands of any instructions that they modify, so that
+they are safe to use on modules that share memory with their words (see [ParseWords]).
+
+	err := module.Apply(
+		spirv.Bake(constants),
+		spirv.StripDebug,
+		spirv.EliminateDeadCode,
+		spirv.CompactIDs,
+	)
+*/
+type Pass func(*Module) error
+
+// Apply the passes to the module, in order.
+func (m *Module) Apply(passes ...Pass) error {
+	for _, pass := range passes {
+		if err := pass(m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Apply the passes to each stage of the bundle, returning a new bundle with the results.
+func (b Bundle) Apply(passes ...Pass) (Bundle, error) {
+	result := make(Bundle, len(b))
+	for model, words := range b {
+		module, err := ParseWords(words)
+		if err != nil {
+			return nil, fmt.Errorf("spirv: %v stage: %w", model, err)
+		}
+		if err := module.Apply(passes...); err != nil {
+			return nil, fmt.Errorf("spirv: %v stage: %w", model, err)
+		}
+		result[model] = module.Words()
+	}
+	return result, nil
+}
+
+// Words encodes the module into words.
+func (m *Module) Words() []uint32 {
+	size := 5
+	for _, inst := range m.Instructions {
+		size += 1 + len(inst.Operands)
+	}
+	words := make([]uint32, 0, size)
+	words = append(words, Magic, m.Version, m.Generator, m.Bound, m.Schema)
+	for _, inst := range m.Instructions {
+		words = append(words, uint32(len(inst.Operands)+1)<<16|uint32(inst.Opcode))
+		words = append(words, inst.Operands...)
+	}
+	return words
+}
+
+// Bytes encodes the module into its little endian binary representation.
+func (m *Module) Bytes() []byte {
+	words := m.Words()
+	code := make([]byte, len(words)*4)
+	for i, word := range words {
+		binary.LittleEndian.PutUint32(code[i*4:], word)
+	}
+	return code
+}
+
+// index of the type declarations and result types within a module.
+type index struct {
+	types  map[uint32]Instruction // type declarations by <id>.
+	values map[uint32]uint32      // result type of each result <id>.
+}
+
+func newIndex(m *Module) index {
+	ix := index{
+		types:  make(map[uint32]Instruction),
+		values: make(map[uint32]uint32),
+	}
+	for _, inst := range m.Instructions {
+		info, ok := grammar[inst.Opcode]
+		if !ok || len(info.operands) == 0 {
+			continue
+		}
+		switch {
+		case info.operands[0].kind == operandResult && len(inst.Operands) > 0:
+			ix.types[inst.Operands[0]] = inst
+		case info.operands[0].kind == operandResultType && len(inst.Operands) > 1:
+			ix.values[inst.Operands[1]] = inst.Operands[0]
+		}
+	}
+	return ix
+}
+
+// selectorWidth returns the number of words of the literals matched against a switch selector.
+func (ix index) selectorWidth(id uint32) int {
+	if decl := ix.types[ix.values[id]]; decl.Opcode == OpTypeInt && len(decl.Operands) > 1 && decl.Operands[1] > 32 {
+		return 2
+	}
+	return 1
+}
+
+// decodeAll decodes the operands of every instruction in the module, failing on any unknown instructions, as
+// their <id> operands cannot be identified.
+func (m *Module) decodeAll() ([][]operand, error) {
+	ix := newIndex(m)
+	decoded := make([][]operand, len(m.Instructions))
+	for i, inst := range m.Instructions {
+		if _, ok := grammar[inst.Opcode]; !ok {
+			return nil, fmt.Errorf("spirv: unsupported instruction %v", inst.Opcode)
+		}
+		operands, err := inst.decode(ix.selectorWidth)
+		if err != nil {
+			return nil, err
+		}
+		decoded[i] = operands
+	}
+	return decoded, nil
+}
+
+// isDebug reports whether the opcode is a debug instruction, which has no effect on the semantics of the module.
+func isDebug(op Opcode) bool {
+	switch op {
+	case OpSourceContinued, OpSource, OpSourceExtension, OpName, OpMemberName, OpLine, OpNoLine, OpModuleProcessed:
+		return true
+	}
+	return false
+}
+
+// isAnnotation reports whether the opcode describes the <id> named by its first operand, without using it.
+func isAnnotation(op Opcode) bool {
+	switch op {
+	case OpName, OpMemberName, OpDecorate, OpMemberDecorate, OpDecorateId, OpDecorateString, OpMemberDecorateString,
+		OpGroupDecorate, OpGroupMemberDecorate, OpTypeForwardPointer:
+		return true
+	}
+	return false
+}
+
+/*
+StripDebug removes debug instructions (OpSource, OpName, OpLine and friends) along with any OpString that
+is no longer referenced. Reflection of a stripped module will not include the names of bindings, blocks
+or specialization constants.
+*/
+func StripDebug(m *Module) error {
+	m.Instructions = slices.DeleteFunc(m.Instructions, func(inst Instruction) bool { return isDebug(inst.Opcode) })
+	strings := make(map[uint32]bool)
+	for _, inst := range m.Instructions {
+		if inst.Opcode == OpString && len(inst.Operands) > 0 {
+			strings[inst.Operands[0]] = true
+		}
+	}
+	if len(strings) == 0 {
+		return nil
+	}
+	decoded, err := m.decodeAll()
+	if err != nil {
+		return err
+	}
+	referenced := make(map[uint32]bool)
+	for _, operands := range decoded {
+		for _, operand := range operands {
+			if operand.kind == operandID && strings[operand.words[0]] {
+				referenced[operand.words[0]] = true
+			}
+		}
+	}
+	m.Instructions = slices.DeleteFunc(m.Instructions, func(inst Instruction) bool {
+		return inst.Opcode == OpString && len(inst.Operands) > 0 && !referenced[inst.Operands[0]]
+	})
+	return nil
+}
+
+/*
+EliminateDeadCode removes functions that cannot be reached from an entry point, along with any global
+variables, types and constants that are not used (and their names and decorations). Variables listed in the
+interface of an entry point are always kept, so that the interface of the module does not change, as are
+constants and variables decorated as built-ins.
+*/
+func EliminateDeadCode(m *Module) error {
+	decoded, err := m.decodeAll()
+	if err != nil {
+		return err
+	}
+	var (
+		definitions = make(map[uint32]int)         // global <id> to instruction index.
+		functions   = make(map[uint32][2]int)      // function <id> to instruction range.
+		annotations = make(map[uint32][]int)       // <id> to the annotations that target it.
+		function    = make([]uint32, len(decoded)) // function <id> containing each instruction, or zero.
+		live        = make(map[uint32]bool)
+		work        []uint32
+	)
+	mark := func(operands []operand, skip int) {
+		for _, operand := range operands[skip:] {
+			if operand.kind == operandID || operand.kind == operandResultType {
+				if id := operand.words[0]; !live[id] {
+					live[id] = true
+					work = append(work, id)
+				}
+			}
+		}
+	}
+	var current uint32
+	for i, inst := range m.Instructions {
+		operands := decoded[i]
+		switch {
+		case inst.Opcode == OpFunction:
+			current = result(operands)
+			functions[current] = [2]int{i, i}
+		case current != 0:
+			if inst.Opcode == OpFunctionEnd {
+				r := functions[current]
+				functions[current] = [2]int{r[0], i}
+			}
+		case isAnnotation(inst.Opcode) && len(operands) > 0:
+			target := operands[0].words[0]
+			annotations[target] = append(annotations[target], i)
+			if inst.Opcode == OpDecorate && len(inst.Operands) > 1 && Decoration(inst.Operands[1]) == DecorationBuiltIn {
+				live[target] = true // such as the WorkgroupSize, which is only referenced by its decoration.
+			}
+		case inst.Opcode == OpDecorationGroup:
+			live[result(operands)] = true
+		case result(operands) != 0:
+			definitions[result(operands)] = i
+		default:
+			mark(operands, 0) // entry points, execution modes and other global instructions are roots.
+		}
+		function[i] = current
+		if inst.Opcode == OpFunctionEnd {
+			current = 0
+		}
+	}
+	for id := range live {
+		work = append(work, id)
+	}
+	for len(work) > 0 {
+		id := work[len(work)-1]
+		work = work[:len(work)-1]
+		if i, ok := definitions[id]; ok {
+			mark(decoded[i], 0)
+		}
+		if r, ok := functions[id]; ok {
+			for i := r[0]; i <= r[1]; i++ {
+				mark(decoded[i], 0)
+			}
+		}
+		for _, i := range annotations[id] {
+			if op := m.Instructions[i].Opcode; op != OpGroupDecorate && op != OpGroupMemberDecorate {
+				mark(decoded[i], 1)
+			}
+		}
+	}
+	kept := m.Instructions[:0]
+	for i, inst := range m.Instructions {
+		operands := decoded[i]
+		switch {
+		case function[i] != 0:
+			if !live[function[i]] {
+				continue
+			}
+		case inst.Opcode == OpGroupDecorate || inst.Opcode == OpGroupMemberDecorate:
+			inst = filterGroupDecorate(inst, live)
+			if len(inst.Operands) < 2 {
+				continue
+			}
+		case isAnnotation(inst.Opcode) && len(operands) > 0:
+			if !live[operands[0].words[0]] {
+				continue
+			}
+		case result(operands) != 0:
+			if !live[result(operands)] {
+				continue
+			}
+		}
+		kept = append(kept, inst)
+	}
+	clear(m.Instructions[len(kept):])
+	m.Instructions = kept
+	return nil
+}
+
+// filterGroupDecorate removes any dead targets from an OpGroupDecorate or OpGroupMemberDecorate.
+func filterGroupDecorate(inst Instruction, live map[uint32]bool) Instruction {
+	stride := 1
+	if inst.Opcode == OpGroupMemberDecorate {
+		stride = 2
+	}
+	operands := []uint32{inst.Operands[0]}
+	for i := 1; i+stride <= len(inst.Operands); i += stride {
+		if live[inst.Operands[i]] {
+			operands = append(operands, inst.Operands[i:i+stride]...)
+		}
+	}
+	inst.Operands = operands
+	return inst
+}
+
+// result returns the result <id> of a decoded instruction, or zero if it has none.
+func result(operands []operand) uint32 {
+	for _, operand := range operands[:min(2, len(operands))] {
+		if operand.kind == operandResult {
+			return operand.words[0]
+		}
+	}
+	return 0
+}
+
+// CompactIDs renumbers the <id>s of the module in order of their first appearance, so that the bound of the
+// module is as small as possible.
+func CompactIDs(m *Module) error {
+	decoded, err := m.decodeAll()
+	if err != nil {
+		return err
+	}
+	ids := make(map[uint32]uint32)
+	for i := range m.Instructions {
+		inst := &m.Instructions[i]
+		operands := decoded[i]
+		inst.Operands = slices.Clone(inst.Operands)
+		offset := 0
+		for _, operand := range operands {
+			switch operand.kind {
+			case operandResultType, operandResult, operandID:
+				id, ok := ids[operand.words[0]]
+				if !ok {
+					id = uint32(len(ids) + 1)
+					ids[operand.words[0]] = id
+				}
+				inst.Operands[offset] = id
+			}
+			offset += len(operand.words)
+		}
+	}
+	m.Bound = uint32(len(ids) + 1)
+	return nil
+}
+
+/*
+Bake returns a pass that replaces the specialization constants with the given IDs by regular constants with
+the given values, so that the driver doesn't have to specialize them (and so that [EliminateDeadCode] can
+see through them). Composites of baked constants are also baked, any other constant instructions that
+depend on them are left for the driver to evaluate.
+
+Returns an error for each constant with an ID that isn't declared by the module, or with a different type.
+*/
+func Bake(constants rd.Constants) Pass {
+	return func(m *Module) error {
+		reflection, err := m.Reflect()
+		if err != nil {
+			return err
+		}
+		var errs []error
+		for id, constant := range constants {
+			sc := reflection.SpecializationConstant(uint32(id))
+			if id < 0 || sc == nil {
+				errs = append(errs, fmt.Errorf("specialization constant %d is not declared by the shader", id))
+				continue
+			}
+			if expects, ok := sc.constantType(); !ok || expects != constant.Type() {
+				errs = append(errs, fmt.Errorf("specialization constant %d (%s) expects %v but was given %v", id, sc.Name, sc.Type, constant.Type()))
+			}
+		}
+		if len(errs) > 0 {
+			return errors.Join(errs...)
+		}
+		baked := make(map[uint32]rd.Constant) // result <id> to value.
+		for _, inst := range m.Instructions {
+			if inst.Opcode == OpDecorate && len(inst.Operands) > 2 && Decoration(inst.Operands[1]) == DecorationSpecId {
+				if constant, ok := constants[int(inst.Operands[2])]; ok {
+					baked[inst.Operands[0]] = constant
+				}
+			}
+		}
+		if len(baked) == 0 {
+			return nil
+		}
+		constant := make(map[uint32]bool) // <id>s of non-specializable constants.
+		for i := range m.Instructions {
+			inst := &m.Instructions[i]
+			if len(inst.Operands) < 2 {
+				continue // malformed, left for [Module.Validate] to report.
+			}
+			switch inst.Opcode {
+			case OpConstantTrue, OpConstantFalse, OpConstant, OpConstantComposite, OpConstantSampler, OpConstantNull:
+				constant[inst.Operands[1]] = true
+			case OpSpecConstantTrue, OpSpecConstantFalse:
+				if value, ok := baked[inst.Operands[1]]; ok {
+					inst.Opcode = OpConstantFalse
+					if value.Bits() != 0 {
+						inst.Opcode = OpConstantTrue
+					}
+					constant[inst.Operands[1]] = true
+				}
+			case OpSpecConstant:
+				if value, ok := baked[inst.Operands[1]]; ok {
+					inst.Opcode = OpConstant
+					inst.Operands = []uint32{inst.Operands[0], inst.Operands[1], value.Bits()}
+					constant[inst.Operands[1]] = true
+				}
+			case OpSpecConstantComposite:
+				if !slices.ContainsFunc(inst.Operands[2:], func(id uint32) bool { return !constant[id] }) {
+					inst.Opcode = OpConstantComposite
+					constant[inst.Operands[1]] = true
+				}
+			}
+		}
+		m.Instructions = slices.DeleteFunc(m.Instructions, func(inst Instruction) bool {
+			if inst.Opcode != OpDecorate || len(inst.Operands) < 2 || Decoration(inst.Operands[1]) != DecorationSpecId {
+				return false
+			}
+			_, ok := baked[inst.Operands[0]]
+			return ok
+		})
+		return nil
+	}
+}
@@ -0,0 +1,76 @@
+package spirv
+
+import (
+	"testing"
+
+	"grow.graphics/rd"
+)
+
+func TestEliminateDeadCodeWorkgroupSize(t *testing.T) {
+	m := computeModule()
+	if err := m.Apply(EliminateDeadCode); err != nil {
+		t.Fatal(err)
+	}
+	kept := make(map[Opcode]int)
+	for _, inst := range m.Instructions {
+		kept[inst.Opcode]++
+	}
+	if kept[OpSpecConstantComposite] != 1 || kept[OpSpecConstant] != 1 || kept[OpDecorate] != 2 {
+		t.Errorf("the workgroup size was removed: %v", m.Instructions)
+	}
+	if kept[OpSpecConstantOp] != 0 {
+		t.Errorf("the unused OpSpecConstantOp was kept")
+	}
+	if err := m.Validate(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCompactIDsSpecConstantOp(t *testing.T) {
+	m := computeModule()
+	m.Bound = 100
+	for i := range m.Instructions {
+		inst := &m.Instructions[i]
+		operands, err := inst.decode(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		offset := 0
+		for _, operand := range operands {
+			switch operand.kind {
+			case operandResultType, operandResult, operandID:
+				inst.Operands[offset] += 50
+			}
+			offset += len(operand.words)
+		}
+	}
+	if err := m.Apply(CompactIDs); err != nil {
+		t.Fatal(err)
+	}
+	if m.Bound != 11 {
+		t.Errorf("got bound %d, want 11", m.Bound)
+	}
+	for _, inst := range m.Instructions {
+		if inst.Opcode == OpSpecConstantOp {
+			if ops := inst.Operands; ops[2] != 81 || ops[4] != 0 {
+				t.Errorf("the literals of %v were renumbered: %v", inst.Opcode, ops)
+			}
+		}
+	}
+	if err := m.Validate(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestBakeTruncatedConstant(t *testing.T) {
+	m := computeModule()
+	m.Instructions = append(m.Instructions[:12:12], append([]Instruction{{OpConstantNull, []uint32{4}}}, m.Instructions[12:]...)...)
+	if err := m.Apply(Bake(rd.Constants{0: rd.ConstantUint(8)})); err != nil {
+		t.Fatal(err)
+	}
+	for _, inst := range m.Instructions {
+		if inst.Opcode == OpSpecConstant {
+			t.Errorf("%%x was not baked")
+		}
+	}
+}
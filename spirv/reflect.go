@@ -0,0 +1,668 @@
+package spirv
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Reflection describes the interface of a SPIR-V module.
+type Reflection struct {
+	EntryPoints             []EntryPoint
+	Bindings                []Binding                // sorted by set, then binding.
+	PushConstants           *Block                   // nil if the module has no push constant block.
+	SpecializationConstants []SpecializationConstant // sorted by ID.
+}
+
+// EntryPoint of a SPIR-V module.
+type EntryPoint struct {
+	Name          string
+	Model         ExecutionModel
+	WorkgroupSize [3]uint32  // only for [ExecutionModelGLCompute], zero if unknown.
+	Inputs        []Location // sorted by location, excluding built-ins.
+	Outputs       []Location // sorted by location, excluding built-ins.
+}
+
+// Location of a shader input or output variable.
+type Location struct {
+	Name      string
+	Location  uint32
+	Component uint32
+	Type      *Type
+	Flat      bool // no interpolation.
+}
+
+// Descriptor type of a [Binding].
+type Descriptor int
+
+const (
+	DescriptorSampler                  Descriptor = iota // sampler
+	DescriptorSamplerWithTexture                         // sampler2D
+	DescriptorTexture                                    // texture2D
+	DescriptorImage                                      // image2D
+	DescriptorTextureBuffer                              // textureBuffer
+	DescriptorSamplerWithTextureBuffer                   // samplerBuffer
+	DescriptorImageBuffer                                // imageBuffer
+	DescriptorUniformBuffer                              // uniform block
+	DescriptorStorageBuffer                              // buffer block
+	DescriptorInputAttachment                            // subpassInput
+	DescriptorAccelerationStructure                      // accelerationStructureEXT
+)
+
+// String returns the name of the descriptor type.
+func (d Descriptor) String() string {
+	switch d {
+	case DescriptorSampler:
+		return "Sampler"
+	case DescriptorSamplerWithTexture:
+		return "SamplerWithTexture"
+	case DescriptorTexture:
+		return "Texture"
+	case DescriptorImage:
+		return "Image"
+	case DescriptorTextureBuffer:
+		return "TextureBuffer"
+	case DescriptorSamplerWithTextureBuffer:
+		return "SamplerWithTextureBuffer"
+	case DescriptorImageBuffer:
+		return "ImageBuffer"
+	case DescriptorUniformBuffer:
+		return "UniformBuffer"
+	case DescriptorStorageBuffer:
+		return "StorageBuffer"
+	case DescriptorInputAttachment:
+		return "InputAttachment"
+	case DescriptorAccelerationStructure:
+		return "AccelerationStructure"
+	default:
+		return fmt.Sprintf("Descriptor(%d)", int(d))
+	}
+}
+
+// Binding of a resource to a descriptor set, in rd terms, a Variable within a set of Variables.
+type Binding struct {
+	Name       string
+	Set        uint32 // corresponds to [rd.VariableLevel].
+	Binding    uint32
+	Descriptor Descriptor
+	Count      int   // number of descriptors in the binding, zero for a runtime-sized array.
+	Type       *Type // type of each descriptor.
+}
+
+// Block of explicitly laid out memory, such as a push constant block.
+type Block struct {
+	Name string
+	Size int   // in bytes.
+	Type *Type // [KindStruct], with member offsets.
+}
+
+// SpecializationConstant that can be overridden when creating a pipeline.
+type SpecializationConstant struct {
+	ID      uint32 // from [DecorationSpecId].
+	Name    string
+	Type    *Type  // scalar type of the constant.
+	Default uint64 // bits of the default value, 0 or 1 for booleans.
+}
+
+// EntryPoint returns the first entry point with the given execution model, or nil.
+func (r *Reflection) EntryPoint(model ExecutionModel) *EntryPoint {
+	for i := range r.EntryPoints {
+		if r.EntryPoints[i].Model == model {
+			return &r.EntryPoints[i]
+		}
+	}
+	return nil
+}
+
+// Binding returns the binding at the given set and binding number, or nil.
+func (r *Reflection) Binding(set, binding uint32) *Binding {
+	for i := range r.Bindings {
+		if r.Bindings[i].Set == set && r.Bindings[i].Binding == binding {
+			return &r.Bindings[i]
+		}
+	}
+	return nil
+}
+
+// SpecializationConstant returns the specialization constant with the given ID, or nil.
+func (r *Reflection) SpecializationConstant(id uint32) *SpecializationConstant {
+	for i := range r.SpecializationConstants {
+		if r.SpecializationConstants[i].ID == id {
+			return &r.SpecializationConstants[i]
+		}
+	}
+	return nil
+}
+
+// Reflect parses the SPIR-V code and reflects on it, see [Module.Reflect].
+func Reflect(code []byte) (*Reflection, error) {
+	module, err := Parse(code)
+	if err != nil {
+		return nil, err
+	}
+	return module.Reflect()
+}
+
+type decorations map[Decoration][]uint32
+
+type constant struct {
+	typ   uint32
+	value []uint32
+	spec  bool
+}
+
+type variable struct {
+	id      uint32
+	typ     uint32
+	storage StorageClass
+}
+
+type reflector struct {
+	names             map[uint32]string
+	memberNames       map[[2]uint32]string
+	decorations       map[uint32]decorations
+	memberDecorations map[[2]uint32]decorations
+	types             map[uint32]*Type
+	constants         map[uint32]constant
+	variables         map[uint32]variable
+	order             []uint32 // of variables
+}
+
+func (r *reflector) decorate(id uint32, decoration Decoration, operands []uint32) {
+	if r.decorations[id] == nil {
+		r.decorations[id] = make(decorations)
+	}
+	r.decorations[id][decoration] = operands
+}
+
+func (r *reflector) decorateMember(id, member uint32, decoration Decoration, operands []uint32) {
+	key := [2]uint32{id, member}
+	if r.memberDecorations[key] == nil {
+		r.memberDecorations[key] = make(decorations)
+	}
+	r.memberDecorations[key][decoration] = operands
+}
+
+// literal returns the first literal of the decoration and whether it was present.
+func (d decorations) literal(decoration Decoration) (uint32, bool) {
+	operands, ok := d[decoration]
+	if !ok || len(operands) == 0 {
+		return 0, ok
+	}
+	return operands[0], true
+}
+
+func (r *reflector) typ(id uint32) *Type {
+	t, ok := r.types[id]
+	if !ok {
+		t = &Type{ID: id}
+		r.types[id] = t
+	}
+	return t
+}
+
+// scalar returns the value of a scalar constant, as long as it was declared.
+func (r *reflector) scalar(id uint32) (uint64, bool) {
+	c, ok := r.constants[id]
+	if !ok {
+		return 0, false
+	}
+	var value uint64
+	for i, word := range c.value {
+		if i < 2 {
+			value |= uint64(word) << (32 * i)
+		}
+	}
+	return value, true
+}
+
+/*
+Reflect on the module, reporting its entry points, descriptor bindings, push constant block,
+specialization constants and input/output locations. Returns an error if the module declares more
+than one push constant block.
+*/
+func (m *Module) Reflect() (*Reflection, error) {
+	r := reflector{
+		names:             make(map[uint32]string),
+		memberNames:       make(map[[2]uint32]string),
+		decorations:       make(map[uint32]decorations),
+		memberDecorations: make(map[[2]uint32]decorations),
+		types:             make(map[uint32]*Type),
+		constants:         make(map[uint32]constant),
+		variables:         make(map[uint32]variable),
+	}
+	var (
+		reflection Reflection
+		entries    = make(map[uint32][]int) // function ID to index of entry points
+		interfaces [][]uint32
+		modes      []Instruction
+		workgroup  *[3]uint32 // decorated with BuiltInWorkgroupSize
+	)
+	short := func(inst Instruction, n int) error {
+		if len(inst.Operands) < n {
+			return fmt.Errorf("spirv: Op(%d) requires at least %d operands, found %d", inst.Opcode, n, len(inst.Operands))
+		}
+		return nil
+	}
+	for _, inst := range m.Instructions {
+		ops := inst.Operands
+		switch inst.Opcode {
+		case OpEntryPoint:
+			if err := short(inst, 3); err != nil {
+				return nil, err
+			}
+			name, n := String(ops[2:])
+			entries[ops[1]] = append(entries[ops[1]], len(reflection.EntryPoints))
+			reflection.EntryPoints = append(reflection.EntryPoints, EntryPoint{
+				Name:  name,
+				Model: ExecutionModel(ops[0]),
+			})
+			interfaces = append(interfaces, ops[2+n:])
+		case OpExecutionMode, OpExecutionModeId:
+			if err := short(inst, 2); err != nil {
+				return nil, err
+			}
+			modes = append(modes, inst)
+		case OpName:
+			if err := short(inst, 1); err != nil {
+				return nil, err
+			}
+			r.names[ops[0]], _ = String(ops[1:])
+		case OpMemberName:
+			if err := short(inst, 2); err != nil {
+				return nil, err
+			}
+			r.memberNames[[2]uint32{ops[0], ops[1]}], _ = String(ops[2:])
+		case OpDecorate, OpDecorateId, OpDecorateString:
+			if err := short(inst, 2); err != nil {
+				return nil, err
+			}
+			r.decorate(ops[0], Decoration(ops[1]), ops[2:])
+		case OpMemberDecorate, OpMemberDecorateString:
+			if err := short(inst, 3); err != nil {
+				return nil, err
+			}
+			r.decorateMember(ops[0], ops[1], Decoration(ops[2]), ops[3:])
+		case OpGroupDecorate:
+			if err := short(inst, 1); err != nil {
+				return nil, err
+			}
+			for _, target := range ops[1:] {
+				for decoration, operands := range r.decorations[ops[0]] {
+					r.decorate(target, decoration, operands)
+				}
+			}
+		case OpGroupMemberDecorate:
+			if err := short(inst, 1); err != nil {
+				return nil, err
+			}
+			for i := 1; i+1 < len(ops); i += 2 {
+				for decoration, operands := range r.decorations[ops[0]] {
+					r.decorateMember(ops[i], ops[i+1], decoration, operands)
+				}
+			}
+		case OpTypeVoid, OpTypeBool, OpTypeSampler, OpTypeAccelerationStructureKHR:
+			if err := short(inst, 1); err != nil {
+				return nil, err
+			}
+			r.typ(ops[0]).Kind = map[Opcode]Kind{
+				OpTypeVoid:                     KindVoid,
+				OpTypeBool:                     KindBool,
+				OpTypeSampler:                  KindSampler,
+				OpTypeAccelerationStructureKHR: KindAccelerationStructure,
+			}[inst.Opcode]
+		case OpTypeInt:
+			if err := short(inst, 3); err != nil {
+				return nil, err
+			}
+			t := r.typ(ops[0])
+			t.Kind, t.Width, t.Signed = KindInt, int(ops[1]), ops[2] != 0
+		case OpTypeFloat:
+			if err := short(inst, 2); err != nil {
+				return nil, err
+			}
+			t := r.typ(ops[0])
+			t.Kind, t.Width = KindFloat, int(ops[1])
+		case OpTypeVector, OpTypeMatrix:
+			if err := short(inst, 3); err != nil {
+				return nil, err
+			}
+			t := r.typ(ops[0])
+			t.Kind, t.Elem, t.Length = KindVector, r.typ(ops[1]), int(ops[2])
+			if inst.Opcode == OpTypeMatrix {
+				t.Kind = KindMatrix
+			}
+		case OpTypeImage:
+			if err := short(inst, 8); err != nil {
+				return nil, err
+			}
+			t := r.typ(ops[0])
+			t.Kind, t.Elem = KindImage, r.typ(ops[1])
+			t.Image = Image{
+				Dim:     Dim(ops[2]),
+				Depth:   ops[3],
+				Arrayed: ops[4] != 0,
+				MS:      ops[5] != 0,
+				Sampled: ops[6],
+				Format:  ops[7],
+			}
+		case OpTypeSampledImage:
+			if err := short(inst, 2); err != nil {
+				return nil, err
+			}
+			t := r.typ(ops[0])
+			t.Kind, t.Elem = KindSampledImage, r.typ(ops[1])
+		case OpTypeArray:
+			if err := short(inst, 3); err != nil {
+				return nil, err
+			}
+			t := r.typ(ops[0])
+			t.Kind, t.Elem = KindArray, r.typ(ops[1])
+			length, _ := r.scalar(ops[2])
+			t.Length = int(length)
+		case OpTypeRuntimeArray:
+			if err := short(inst, 2); err != nil {
+				return nil, err
+			}
+			t := r.typ(ops[0])
+			t.Kind, t.Elem = KindRuntimeArray, r.typ(ops[1])
+		case OpTypeStruct:
+			if err := short(inst, 1); err != nil {
+				return nil, err
+			}
+			t := r.typ(ops[0])
+			t.Kind = KindStruct
+			t.Members = make([]Member, len(ops)-1)
+			for i, member := range ops[1:] {
+				t.Members[i].Type = r.typ(member)
+			}
+		case OpTypePointer:
+			if err := short(inst, 3); err != nil {
+				return nil, err
+			}
+			t := r.typ(ops[0])
+			t.Kind, t.StorageClass, t.Elem = KindPointer, StorageClass(ops[1]), r.typ(ops[2])
+		case OpTypeFunction:
+			if err := short(inst, 2); err != nil {
+				return nil, err
+			}
+			t := r.typ(ops[0])
+			t.Kind, t.Elem = KindFunction, r.typ(ops[1])
+		case OpConstant, OpSpecConstant:
+			if err := short(inst, 2); err != nil {
+				return nil, err
+			}
+			r.constants[ops[1]] = constant{typ: ops[0], value: ops[2:], spec: inst.Opcode == OpSpecConstant}
+		case OpConstantTrue, OpSpecConstantTrue:
+			if err := short(inst, 2); err != nil {
+				return nil, err
+			}
+			r.constants[ops[1]] = constant{typ: ops[0], value: []uint32{1}, spec: inst.Opcode == OpSpecConstantTrue}
+		case OpConstantFalse, OpSpecConstantFalse:
+			if err := short(inst, 2); err != nil {
+				return nil, err
+			}
+			r.constants[ops[1]] = constant{typ: ops[0], value: []uint32{0}, spec: inst.Opcode == OpSpecConstantFalse}
+		case OpConstantComposite, OpSpecConstantComposite:
+			if err := short(inst, 2); err != nil {
+				return nil, err
+			}
+			if builtin, ok := r.decorations[ops[1]].literal(DecorationBuiltIn); ok && BuiltIn(builtin) == BuiltInWorkgroupSize && len(ops) >= 5 {
+				workgroup = new([3]uint32)
+				for i := range workgroup {
+					value, _ := r.scalar(ops[2+i])
+					workgroup[i] = uint32(value)
+				}
+			}
+		case OpVariable:
+			if err := short(inst, 3); err != nil {
+				return nil, err
+			}
+			r.variables[ops[1]] = variable{id: ops[1], typ: ops[0], storage: StorageClass(ops[2])}
+			r.order = append(r.order, ops[1])
+		}
+	}
+	for id, t := range r.types {
+		t.Name = r.names[id]
+		d := r.decorations[id]
+		if stride, ok := d.literal(DecorationArrayStride); ok {
+			t.ArrayStride = int(stride)
+		}
+		_, t.Block = d[DecorationBlock]
+		_, t.BufferBlock = d[DecorationBufferBlock]
+		for i := range t.Members {
+			member := &t.Members[i]
+			member.Name = r.memberNames[[2]uint32{id, uint32(i)}]
+			md := r.memberDecorations[[2]uint32{id, uint32(i)}]
+			if offset, ok := md.literal(DecorationOffset); ok {
+				member.Offset = int(offset)
+			}
+			if stride, ok := md.literal(DecorationMatrixStride); ok {
+				member.MatrixStride = int(stride)
+			}
+			_, member.RowMajor = md[DecorationRowMajor]
+			_, member.BuiltIn = md[DecorationBuiltIn]
+		}
+	}
+	for _, inst := range modes {
+		ops := inst.Operands
+		mode := ExecutionMode(ops[1])
+		if mode != ExecutionModeLocalSize && mode != ExecutionModeLocalSizeId {
+			continue
+		}
+		if len(ops) < 5 {
+			return nil, fmt.Errorf("spirv: execution mode %d requires three sizes", mode)
+		}
+		var size [3]uint32
+		for i := range size {
+			size[i] = ops[2+i]
+			if inst.Opcode == OpExecutionModeId {
+				value, _ := r.scalar(ops[2+i])
+				size[i] = uint32(value)
+			}
+		}
+		for _, index := range entries[ops[0]] {
+			reflection.EntryPoints[index].WorkgroupSize = size
+		}
+	}
+	if workgroup != nil {
+		// the WorkgroupSize built-in takes precedence over any execution modes.
+		for i := range reflection.EntryPoints {
+			if reflection.EntryPoints[i].Model == ExecutionModelGLCompute {
+				reflection.EntryPoints[i].WorkgroupSize = *workgroup
+			}
+		}
+	}
+	for i, ids := range interfaces {
+		entry := &reflection.EntryPoints[i]
+		for _, id := range ids {
+			v, ok := r.variables[id]
+			if !ok || (v.storage != StorageClassInput && v.storage != StorageClassOutput) {
+				continue
+			}
+			if v.storage == StorageClassInput {
+				entry.Inputs = append(entry.Inputs, r.locations(v)...)
+			} else {
+				entry.Outputs = append(entry.Outputs, r.locations(v)...)
+			}
+		}
+		sortLocations(entry.Inputs)
+		sortLocations(entry.Outputs)
+	}
+	var push uint32 // push constant variable.
+	for _, id := range r.order {
+		v := r.variables[id]
+		pointer := r.typ(v.typ)
+		if pointer.Kind != KindPointer {
+			return nil, fmt.Errorf("spirv: variable %%%d does not have a pointer type", id)
+		}
+		switch v.storage {
+		case StorageClassPushConstant:
+			name := r.names[id]
+			if name == "" {
+				name = pointer.Elem.Name
+			}
+			if push != 0 {
+				return nil, fmt.Errorf("spirv: module declares more than one push constant block (%%%d and %%%d)", push, id)
+			}
+			push = id
+			reflection.PushConstants = &Block{
+				Name: name,
+				Size: pointer.Elem.Size(),
+				Type: pointer.Elem,
+			}
+		case StorageClassUniformConstant, StorageClassUniform, StorageClassStorageBuffer:
+			binding, ok := r.binding(v, pointer.Elem)
+			if ok {
+				reflection.Bindings = append(reflection.Bindings, binding)
+			}
+		}
+	}
+	sort.Slice(reflection.Bindings, func(i, j int) bool {
+		a, b := reflection.Bindings[i], reflection.Bindings[j]
+		if a.Set != b.Set {
+			return a.Set < b.Set
+		}
+		return a.Binding < b.Binding
+	})
+	for id, c := range r.constants {
+		if !c.spec {
+			continue
+		}
+		specID, ok := r.decorations[id].literal(DecorationSpecId)
+		if !ok {
+			continue
+		}
+		value, _ := r.scalar(id)
+		reflection.SpecializationConstants = append(reflection.SpecializationConstants, SpecializationConstant{
+			ID:      specID,
+			Name:    r.names[id],
+			Type:    r.typ(c.typ),
+			Default: value,
+		})
+	}
+	sort.Slice(reflection.SpecializationConstants, func(i, j int) bool {
+		return reflection.SpecializationConstants[i].ID < reflection.SpecializationConstants[j].ID
+	})
+	return &reflection, nil
+}
+
+/*
+locations returns the locations of an input/output variable, excluding built-ins. The members of an IO block
+are listed separately (named after the block's instance), each at the location of its own Location decoration,
+or else at the location following the previous member.
+*/
+func (r *reflector) locations(v variable) []Location {
+	d := r.decorations[v.id]
+	if _, ok := d[DecorationBuiltIn]; ok {
+		return nil
+	}
+	location, ok := d.literal(DecorationLocation)
+	component, _ := d.literal(DecorationComponent)
+	_, flat := d[DecorationFlat]
+	t := r.typ(v.typ).Elem
+	block := t
+	for block != nil && (block.Kind == KindArray || block.Kind == KindRuntimeArray) {
+		block = block.Elem
+	}
+	if block == nil || block.Kind != KindStruct || !block.Block {
+		if !ok {
+			return nil
+		}
+		return []Location{{Name: r.names[v.id], Location: location, Component: component, Type: t, Flat: flat}}
+	}
+	var locations []Location
+	for i, member := range block.Members {
+		md := r.memberDecorations[[2]uint32{block.ID, uint32(i)}]
+		if l, found := md.literal(DecorationLocation); found {
+			location, ok = l, true
+		}
+		if member.BuiltIn || !ok {
+			continue
+		}
+		name := member.Name
+		if instance := r.names[v.id]; instance != "" {
+			name = instance + "." + name
+		}
+		component, _ := md.literal(DecorationComponent)
+		_, memberFlat := md[DecorationFlat]
+		locations = append(locations, Location{
+			Name:      name,
+			Location:  location,
+			Component: component,
+			Type:      member.Type,
+			Flat:      flat || memberFlat,
+		})
+		location += locationCount(member.Type)
+	}
+	return locations
+}
+
+// binding classifies a resource variable, returning false if it does not have a descriptor binding.
+func (r *reflector) binding(v variable, t *Type) (Binding, bool) {
+	d := r.decorations[v.id]
+	number, ok := d.literal(DecorationBinding)
+	if !ok {
+		return Binding{}, false
+	}
+	set, _ := d.literal(DecorationDescriptorSet)
+	binding := Binding{
+		Name:    r.names[v.id],
+		Set:     set,
+		Binding: number,
+		Count:   1,
+	}
+	switch t.Kind {
+	case KindArray:
+		binding.Count = t.Length
+		t = t.Elem
+	case KindRuntimeArray:
+		binding.Count = 0
+		t = t.Elem
+	}
+	binding.Type = t
+	if binding.Name == "" {
+		binding.Name = t.Name
+	}
+	switch t.Kind {
+	case KindSampler:
+		binding.Descriptor = DescriptorSampler
+	case KindSampledImage:
+		binding.Descriptor = DescriptorSamplerWithTexture
+		if t.Elem.Image.Dim == DimBuffer {
+			binding.Descriptor = DescriptorSamplerWithTextureBuffer
+		}
+	case KindImage:
+		switch {
+		case t.Image.Dim == DimSubpassData:
+			binding.Descriptor = DescriptorInputAttachment
+		case t.Image.Dim == DimBuffer && t.Image.Sampled == 2:
+			binding.Descriptor = DescriptorImageBuffer
+		case t.Image.Dim == DimBuffer:
+			binding.Descriptor = DescriptorTextureBuffer
+		case t.Image.Sampled == 2:
+			binding.Descriptor = DescriptorImage
+		default:
+			binding.Descriptor = DescriptorTexture
+		}
+	case KindStruct:
+		if v.storage == StorageClassStorageBuffer || t.BufferBlock {
+			binding.Descriptor = DescriptorStorageBuffer
+		} else {
+			binding.Descriptor = DescriptorUniformBuffer
+		}
+	case KindAccelerationStructure:
+		binding.Descriptor = DescriptorAccelerationStructure
+	default:
+		return Binding{}, false
+	}
+	return binding, true
+}
+
+func sortLocations(locations []Location) {
+	sort.Slice(locations, func(i, j int) bool {
+		if locations[i].Location != locations[j].Location {
+			return locations[i].Location < locations[j].Location
+		}
+		return locations[i].Component < locations[j].Component
+	})
+}
@@ -0,0 +1,65 @@
+package spirv
+
+import "testing"
+
+// blockModule returns a vertex shader that writes to an output block, with its members at locations 2 and 3.
+//
+//	layout(location = 2) out Block { vec4 color; vec2 uv; } v;
+func blockModule() *Module {
+	return &Module{
+		Version: 0x00010000,
+		Bound:   11,
+		Instructions: []Instruction{
+			{OpCapability, []uint32{1}},
+			{OpMemoryModel, []uint32{0, 1}},
+			{OpEntryPoint, []uint32{0, 1, 0x6e69616d, 0, 9}}, // Vertex %main "main" %v
+			{OpName, []uint32{9, 0x76}},                      // %v "v"
+			{OpMemberName, []uint32{7, 0, 0x6f6c6f63, 0x72}}, // %Block 0 "color"
+			{OpMemberName, []uint32{7, 1, 0x7675}},           // %Block 1 "uv"
+			{OpDecorate, []uint32{7, 2}},                     // %Block Block
+			{OpDecorate, []uint32{9, 30, 2}},                 // %v Location 2
+			{OpTypeVoid, []uint32{2}},
+			{OpTypeFunction, []uint32{3, 2}},
+			{OpTypeFloat, []uint32{4, 32}},
+			{OpTypeVector, []uint32{5, 4, 4}},
+			{OpTypeVector, []uint32{6, 4, 2}},
+			{OpTypeStruct, []uint32{7, 5, 6}},
+			{OpTypePointer, []uint32{8, 3, 7}}, // Output
+			{OpVariable, []uint32{8, 9, 3}},
+			{OpFunction, []uint32{2, 1, 0, 3}},
+			{OpLabel, []uint32{10}},
+			{OpReturn, nil},
+			{OpFunctionEnd, nil},
+		},
+	}
+}
+
+func TestReflectBlockMembers(t *testing.T) {
+	reflection, err := blockModule().Reflect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	outputs := reflection.EntryPoints[0].Outputs
+	if len(outputs) != 2 {
+		t.Fatalf("got %d outputs, want 2", len(outputs))
+	}
+	for i, want := range []Location{{Name: "v.color", Location: 2}, {Name: "v.uv", Location: 3}} {
+		if got := outputs[i]; got.Name != want.Name || got.Location != want.Location {
+			t.Errorf("output %d is %s at location %d, want %s at location %d", i, got.Name, got.Location, want.Name, want.Location)
+		}
+	}
+}
+
+func TestReflectPushConstants(t *testing.T) {
+	m := blockModule()
+	m.Bound = 14
+	variables := []Instruction{
+		{OpTypePointer, []uint32{11, 9, 7}}, // PushConstant
+		{OpVariable, []uint32{11, 12, 9}},
+		{OpVariable, []uint32{11, 13, 9}},
+	}
+	m.Instructions = append(m.Instructions[:16], append(variables, m.Instructions[16:]...)...)
+	if _, err := m.Reflect(); err == nil {
+		t.Error("expected an error for two push constant blocks")
+	}
+}
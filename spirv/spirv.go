@@ -0,0 +1,118 @@
+/*
+Package spirv parses SPIR-V modules and reflects on the interface that they expose to the rendering device, such as
+their entry points, descriptor bindings, push constants, specialization constants and vertex inputs.
+
+	module, err := spirv.Parse(code.Vertex())
+	if err != nil {
+		return err
+	}
+	reflection, err := module.Reflect()
+	if err != nil {
+		return err
+	}
+	for _, binding := range reflection.Bindings {
+		fmt.Println(binding.Set, binding.Binding, binding.Descriptor, binding.Type)
+	}
+*/
+package spirv
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// Magic number at the start of every SPIR-V module.
+const Magic = 0x07230203
+
+// Module is a parsed SPIR-V module.
+type Module struct {
+	Version   uint32 // SPIR-V version, 0x00MMmm00 for version MM.mm
+	Generator uint32 // Generator magic number.
+	Bound     uint32 // All IDs in the module are less than this value.
+	Schema    uint32 // Reserved, always zero.
+
+	Instructions []Instruction
+}
+
+// Instruction within a SPIR-V module.
+type Instruction struct {
+	Opcode   Opcode
+	Operands []uint32
+}
+
+// Parse a SPIR-V module from its binary representation. Both little and big endian modules are supported.
+func Parse(code []byte) (*Module, error) {
+	words, err := Words(code)
+	if err != nil {
+		return nil, err
+	}
+	return ParseWords(words)
+}
+
+// Words converts the binary representation of a SPIR-V module into words, according to the endianness
+// of its magic number.
+func Words(code []byte) ([]uint32, error) {
+	if len(code)%4 != 0 {
+		return nil, fmt.Errorf("spirv: module size %d is not a multiple of four", len(code))
+	}
+	if len(code) < 4 {
+		return nil, errors.New("spirv: missing magic number")
+	}
+	var order binary.ByteOrder = binary.LittleEndian
+	if binary.BigEndian.Uint32(code) == Magic {
+		order = binary.BigEndian
+	}
+	words := make([]uint32, len(code)/4)
+	for i := range words {
+		words[i] = order.Uint32(code[i*4:])
+	}
+	return words, nil
+}
+
+// ParseWords parses a SPIR-V module from a slice of words, the resulting instructions will share memory with the slice.
+func ParseWords(words []uint32) (*Module, error) {
+	if len(words) < 5 {
+		return nil, errors.New("spirv: module is too short to contain a header")
+	}
+	if words[0] != Magic {
+		return nil, fmt.Errorf("spirv: invalid magic number %#08x", words[0])
+	}
+	module := &Module{
+		Version:   words[1],
+		Generator: words[2],
+		Bound:     words[3],
+		Schema:    words[4],
+	}
+	for offset := 5; offset < len(words); {
+		count := int(words[offset] >> 16)
+		if count == 0 {
+			return nil, fmt.Errorf("spirv: instruction at word %d has a word count of zero", offset)
+		}
+		if offset+count > len(words) {
+			return nil, fmt.Errorf("spirv: instruction at word %d overruns the end of the module", offset)
+		}
+		module.Instructions = append(module.Instructions, Instruction{
+			Opcode:   Opcode(words[offset] & 0xffff),
+			Operands: words[offset+1 : offset+count : offset+count],
+		})
+		offset += count
+	}
+	return module, nil
+}
+
+// String decodes a literal string from the start of the given operands, returning the string and the
+// number of words it occupied.
+func String(operands []uint32) (string, int) {
+	var buf []byte
+	for i, word := range operands {
+		for shift := 0; shift < 32; shift += 8 {
+			c := byte(word >> shift)
+			if c == 0 {
+				return string(buf), i + 1
+			}
+			buf = append(buf, c)
+		}
+	}
+	return string(buf), len(operands)
+}
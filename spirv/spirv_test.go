@@ -0,0 +1,32 @@
+package spirv
+
+// computeModule returns a compute shader with its workgroup size given by local_size_x_id = 0, as compiled by
+// glslang, along with a specialization constant that extracts the x component of the workgroup size.
+//
+//	%main = 1, %void = 2, %fn = 3, %uint = 4, %v3uint = 5, %x = 6, %uint_1 = 7, %size = 8, %size_x = 9
+func computeModule() *Module {
+	return &Module{
+		Version: 0x00010000,
+		Bound:   11,
+		Instructions: []Instruction{
+			{OpCapability, []uint32{1}},                   // Shader
+			{OpMemoryModel, []uint32{0, 1}},               // Logical GLSL450
+			{OpEntryPoint, []uint32{5, 1, 0x6e69616d, 0}}, // GLCompute %main "main"
+			{OpExecutionMode, []uint32{1, 17, 1, 1, 1}},   // %main LocalSize 1 1 1
+			{OpDecorate, []uint32{6, 1, 0}},               // %x SpecId 0
+			{OpDecorate, []uint32{8, 11, 25}},             // %size BuiltIn WorkgroupSize
+			{OpTypeVoid, []uint32{2}},
+			{OpTypeFunction, []uint32{3, 2}},
+			{OpTypeInt, []uint32{4, 32, 0}},
+			{OpTypeVector, []uint32{5, 4, 3}},
+			{OpSpecConstant, []uint32{4, 6, 1}},
+			{OpConstant, []uint32{4, 7, 1}},
+			{OpSpecConstantComposite, []uint32{5, 8, 6, 7, 7}},
+			{OpSpecConstantOp, []uint32{4, 9, 81, 8, 0}}, // CompositeExtract %size 0
+			{OpFunction, []uint32{2, 1, 0, 3}},
+			{OpLabel, []uint32{10}},
+			{OpReturn, nil},
+			{OpFunctionEnd, nil},
+		},
+	}
+}
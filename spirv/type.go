@@ -0,0 +1,250 @@
+package spirv
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Kind of [Type].
+type Kind int
+
+const (
+	KindUnknown Kind = iota
+	KindVoid
+	KindBool
+	KindInt
+	KindFloat
+	KindVector
+	KindMatrix
+	KindArray
+	KindRuntimeArray
+	KindStruct
+	KindImage
+	KindSampler
+	KindSampledImage
+	KindPointer
+	KindFunction
+	KindAccelerationStructure
+)
+
+// Type declared by a SPIR-V module.
+type Type struct {
+	ID   uint32
+	Kind Kind
+	Name string // from OpName, if available.
+
+	Width  int  // in bits, for [KindInt] and [KindFloat].
+	Signed bool // for [KindInt].
+
+	// Length is the number of components of a vector, the number of columns of a matrix,
+	// or the number of elements in an array.
+	Length int
+
+	// Elem is the component type of a vector, the column type of a matrix, the element type of
+	// an array, the pointee of a pointer, the image of a sampled image or the sampled type of an image.
+	Elem *Type
+
+	ArrayStride  int          // [DecorationArrayStride] of an array, zero if undecorated.
+	StorageClass StorageClass // for [KindPointer].
+	Members      []Member     // for [KindStruct].
+	Block        bool         // [KindStruct] is decorated with Block.
+	BufferBlock  bool         // [KindStruct] is decorated with BufferBlock.
+	Image        Image        // for [KindImage].
+}
+
+// Image describes the operands of an image type.
+type Image struct {
+	Dim     Dim
+	Depth   uint32 // 0 for non-depth, 1 for depth, 2 for unknown.
+	Arrayed bool
+	MS      bool   // multisampled.
+	Sampled uint32 // 1 when used with a sampler, 2 when used for storage.
+	Format  uint32 // SPIR-V ImageFormat, 0 for unknown.
+}
+
+// Member of a structure.
+type Member struct {
+	Name         string // from OpMemberName, if available.
+	Type         *Type
+	Offset       int  // byte offset of the member, from [DecorationOffset].
+	MatrixStride int  // [DecorationMatrixStride] for matrix members.
+	RowMajor     bool // matrix member is decorated with RowMajor.
+	BuiltIn      bool // member is decorated with BuiltIn.
+}
+
+// IsScalar returns true if the type is a bool, integer or floating point number.
+func (t *Type) IsScalar() bool {
+	return t.Kind == KindBool || t.Kind == KindInt || t.Kind == KindFloat
+}
+
+// Scalar returns the scalar type that makes up a scalar, vector or matrix type, or nil.
+func (t *Type) Scalar() *Type {
+	switch {
+	case t.IsScalar():
+		return t
+	case t.Kind == KindVector, t.Kind == KindMatrix:
+		return t.Elem.Scalar()
+	default:
+		return nil
+	}
+}
+
+// Size returns the size of the type in bytes, according to the explicit layout decorations within
+// the module. Returns zero for opaque types and runtime arrays.
+func (t *Type) Size() int {
+	switch t.Kind {
+	case KindBool:
+		return 4
+	case KindInt, KindFloat:
+		return t.Width / 8
+	case KindVector:
+		return t.Length * t.Elem.Size()
+	case KindMatrix:
+		return t.Length * t.Elem.Size()
+	case KindArray:
+		stride := t.ArrayStride
+		if stride == 0 {
+			stride = t.Elem.Size()
+		}
+		return t.Length * stride
+	case KindStruct:
+		size := 0
+		for _, member := range t.Members {
+			if end := member.Offset + member.Size(); end > size {
+				size = end
+			}
+		}
+		return size
+	default:
+		return 0
+	}
+}
+
+// Size returns the size of the member in bytes, taking into account its matrix stride.
+func (m Member) Size() int {
+	if m.Type.Kind != KindMatrix || m.MatrixStride == 0 {
+		return m.Type.Size()
+	}
+	if m.RowMajor {
+		return m.Type.Elem.Length * m.MatrixStride
+	}
+	return m.Type.Length * m.MatrixStride
+}
+
+// String returns the GLSL name of the type, or its SPIR-V name for types without a GLSL equivalent.
+func (t *Type) String() string {
+	switch t.Kind {
+	case KindVoid:
+		return "void"
+	case KindBool:
+		return "bool"
+	case KindInt:
+		name := "int"
+		if !t.Signed {
+			name = "uint"
+		}
+		if t.Width != 32 {
+			name += strconv.Itoa(t.Width) + "_t"
+		}
+		return name
+	case KindFloat:
+		switch t.Width {
+		case 32:
+			return "float"
+		case 64:
+			return "double"
+		default:
+			return "float" + strconv.Itoa(t.Width) + "_t"
+		}
+	case KindVector:
+		return vectorPrefix(t.Elem) + "vec" + strconv.Itoa(t.Length)
+	case KindMatrix:
+		prefix := vectorPrefix(t.Elem.Elem)
+		if t.Length == t.Elem.Length {
+			return prefix + "mat" + strconv.Itoa(t.Length)
+		}
+		return prefix + "mat" + strconv.Itoa(t.Length) + "x" + strconv.Itoa(t.Elem.Length)
+	case KindArray:
+		return t.Elem.String() + "[" + strconv.Itoa(t.Length) + "]"
+	case KindRuntimeArray:
+		return t.Elem.String() + "[]"
+	case KindStruct:
+		if t.Name != "" {
+			return t.Name
+		}
+		return "struct"
+	case KindImage:
+		prefix := ""
+		if t.Elem != nil {
+			prefix = vectorPrefix(t.Elem)
+		}
+		if t.Image.Dim == DimSubpassData {
+			return prefix + "subpassInput"
+		}
+		if t.Image.Sampled == 2 {
+			return prefix + "image" + t.Image.suffix()
+		}
+		return prefix + "texture" + t.Image.suffix()
+	case KindSampler:
+		return "sampler"
+	case KindSampledImage:
+		prefix := ""
+		if t.Elem.Elem != nil {
+			prefix = vectorPrefix(t.Elem.Elem)
+		}
+		name := prefix + "sampler" + t.Elem.Image.suffix()
+		if t.Elem.Image.Depth == 1 {
+			name += "Shadow"
+		}
+		return name
+	case KindPointer:
+		return t.Elem.String() + "*"
+	case KindFunction:
+		return "function"
+	case KindAccelerationStructure:
+		return "accelerationStructureEXT"
+	default:
+		return fmt.Sprintf("%%%d", t.ID)
+	}
+}
+
+func (img Image) suffix() string {
+	var s string
+	switch img.Dim {
+	case Dim1D:
+		s = "1D"
+	case Dim2D:
+		s = "2D"
+	case Dim3D:
+		s = "3D"
+	case DimCube:
+		s = "Cube"
+	case DimRect:
+		s = "2DRect"
+	case DimBuffer:
+		s = "Buffer"
+	}
+	if img.MS {
+		s += "MS"
+	}
+	if img.Arrayed {
+		s += "Array"
+	}
+	return s
+}
+
+// vectorPrefix returns the GLSL prefix for vectors of the given scalar type.
+func vectorPrefix(scalar *Type) string {
+	switch {
+	case scalar.Kind == KindBool:
+		return "b"
+	case scalar.Kind == KindInt && scalar.Signed:
+		return "i"
+	case scalar.Kind == KindInt:
+		return "u"
+	case scalar.Kind == KindFloat && scalar.Width == 64:
+		return "d"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,709 @@
+package spirv
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// validator checks the structure of a module.
+type validator struct {
+	module      *Module
+	errs        []error
+	decoded     [][]operand
+	unknown     bool                              // module contains instructions that are not in the grammar.
+	definitions map[uint32]int                    // result <id> to instruction index.
+	decorations map[uint32]decorations            // by target <id>.
+	members     map[uint32]map[uint32]decorations // by structure <id>, then member.
+}
+
+func (v *validator) errorf(format string, args ...any) {
+	v.errs = append(v.errs, fmt.Errorf(format, args...))
+}
+
+// at reports an error for the instruction at index i.
+func (v *validator) at(i int, format string, args ...any) {
+	v.errs = append(v.errs, fmt.Errorf("instruction %d (%v): %s", i, v.module.Instructions[i].Opcode, fmt.Sprintf(format, args...)))
+}
+
+// definition returns the instruction that defines the <id>, if any.
+func (v *validator) definition(id uint32) (Instruction, bool) {
+	i, ok := v.definitions[id]
+	if !ok {
+		return Instruction{}, false
+	}
+	return v.module.Instructions[i], true
+}
+
+/*
+Validate checks that the module is well-formed, so that malformed SPIR-V is caught before it can reach (and
+crash) a driver. Returns an error for each problem found with:
+
+  - the header: version, bound and schema.
+  - the logical layout: sections appear in order, functions and blocks are properly terminated.
+  - <id>s: below the bound, defined exactly once and defined when referenced.
+  - type declarations: valid widths and component counts, operands that refer to types.
+  - decorations: applied to suitable targets, without conflicting values, with explicit offsets for
+    the members of blocks and with descriptor sets and bindings for resources.
+  - entry points: refer to functions and interface variables, with locations for non-built-in inputs
+    and outputs, and with flat integer inputs for fragment shaders.
+
+This is a structural check, it is not a substitute for the rules checked by spirv-val.
+*/
+func (m *Module) Validate() error {
+	var errs []error
+	for _, err := range m.validate() {
+		errs = append(errs, fmt.Errorf("spirv: %w", err))
+	}
+	return errors.Join(errs...)
+}
+
+func (m *Module) validate() []error {
+	v := validator{
+		module:      m,
+		definitions: make(map[uint32]int),
+		decorations: make(map[uint32]decorations),
+		members:     make(map[uint32]map[uint32]decorations),
+	}
+	v.header()
+	v.decode()
+	v.layout()
+	v.ids()
+	v.types()
+	v.decorate()
+	v.entryPoints()
+	if len(v.errs) == 0 {
+		v.interfaces()
+	}
+	return v.errs
+}
+
+// header checks the version, bound and schema of the module.
+func (v *validator) header() {
+	m := v.module
+	if major, minor := m.Version>>16&0xff, m.Version>>8&0xff; m.Version&0xff0000ff != 0 || major != 1 || minor > 6 {
+		v.errorf("unsupported version %#08x", m.Version)
+	}
+	if m.Bound == 0 || m.Bound > 0x3fffff {
+		v.errorf("bound %d is out of range", m.Bound)
+	}
+	if m.Schema != 0 {
+		v.errorf("schema %d is reserved", m.Schema)
+	}
+}
+
+// decode the operands of each instruction, checking their word counts.
+func (v *validator) decode() {
+	ix := newIndex(v.module)
+	v.decoded = make([][]operand, len(v.module.Instructions))
+	for i, inst := range v.module.Instructions {
+		if _, ok := grammar[inst.Opcode]; !ok {
+			v.unknown = true
+			continue
+		}
+		operands, err := inst.decode(ix.selectorWidth)
+		if err != nil {
+			v.at(i, "%v", err)
+			continue
+		}
+		v.decoded[i] = operands
+	}
+}
+
+// section of the logical layout of a module that the opcode belongs to.
+func section(op Opcode) int {
+	switch op {
+	case OpCapability:
+		return 0
+	case OpExtension:
+		return 1
+	case OpExtInstImport:
+		return 2
+	case OpMemoryModel:
+		return 3
+	case OpEntryPoint:
+		return 4
+	case OpExecutionMode, OpExecutionModeId:
+		return 5
+	case OpString, OpSourceExtension, OpSource, OpSourceContinued:
+		return 6
+	case OpName, OpMemberName:
+		return 7
+	case OpModuleProcessed:
+		return 8
+	case OpDecorate, OpMemberDecorate, OpDecorationGroup, OpGroupDecorate, OpGroupMemberDecorate, OpDecorateId,
+		OpDecorateString, OpMemberDecorateString:
+		return 9
+	case OpFunction:
+		return 11
+	default:
+		return 10
+	}
+}
+
+// isTerminator reports whether the opcode terminates a block.
+func isTerminator(op Opcode) bool {
+	switch op {
+	case OpBranch, OpBranchConditional, OpSwitch, OpKill, OpReturn, OpReturnValue, OpUnreachable, OpTerminateInvocation:
+		return true
+	}
+	return false
+}
+
+// isDeclaration reports whether the opcode declares a type or constant, which can only appear outside of functions.
+func isDeclaration(op Opcode) bool {
+	info, ok := grammar[op]
+	return ok && (strings.HasPrefix(info.name, "Type") || strings.HasPrefix(info.name, "Constant") || strings.HasPrefix(info.name, "SpecConstant"))
+}
+
+// layout checks the order of the instructions in the module, and the structure of functions.
+func (v *validator) layout() {
+	var (
+		last       int
+		inFunction bool
+		inBlock    bool
+		labelled   bool
+		models     int
+	)
+	for i, inst := range v.module.Instructions {
+		op := inst.Opcode
+		if op == OpMemoryModel {
+			models++
+		}
+		if !inFunction {
+			switch {
+			case op == OpLine || op == OpNoLine:
+			case op == OpFunctionEnd:
+				v.at(i, "outside of a function")
+			case section(op) < last:
+				v.at(i, "out of order in the logical layout of the module")
+			default:
+				last = section(op)
+			}
+			if op == OpFunction {
+				inFunction, inBlock, labelled = true, false, false
+			}
+			continue
+		}
+		switch {
+		case op == OpLine || op == OpNoLine:
+		case op == OpFunction:
+			v.at(i, "nested within another function")
+		case op == OpFunctionEnd:
+			if inBlock {
+				v.at(i, "last block of the function is not terminated")
+			}
+			inFunction = false
+		case op == OpFunctionParameter:
+			if labelled {
+				v.at(i, "after the first block of the function")
+			}
+		case section(op) < 10 || isDeclaration(op):
+			v.at(i, "not allowed within a function")
+		case op == OpLabel:
+			if inBlock {
+				v.at(i, "previous block is not terminated")
+			}
+			inBlock, labelled = true, true
+		case !inBlock:
+			v.at(i, "outside of a block")
+		case isTerminator(op):
+			inBlock = false
+		}
+	}
+	if inFunction {
+		v.errorf("last function is missing OpFunctionEnd")
+	}
+	if models != 1 {
+		v.errorf("module must have exactly one OpMemoryModel, found %d", models)
+	}
+}
+
+// ids checks that every <id> is within the bound of the module, defined exactly once and defined when referenced.
+func (v *validator) ids() {
+	bound := v.module.Bound
+	for i, operands := range v.decoded {
+		for _, operand := range operands {
+			if operand.kind != operandResult {
+				continue
+			}
+			id := operand.words[0]
+			if id == 0 || id >= bound {
+				v.at(i, "result %%%d is outside of the bound %d", id, bound)
+			}
+			if previous, ok := v.definitions[id]; ok {
+				v.at(i, "result %%%d is already defined by instruction %d", id, previous)
+				continue
+			}
+			v.definitions[id] = i
+		}
+	}
+	for i, operands := range v.decoded {
+		for _, operand := range operands {
+			if operand.kind != operandID && operand.kind != operandResultType {
+				continue
+			}
+			id := operand.words[0]
+			if id == 0 || id >= bound {
+				v.at(i, "%%%d is outside of the bound %d", id, bound)
+				continue
+			}
+			if _, ok := v.definitions[id]; !ok && !v.unknown {
+				v.at(i, "%%%d is not defined", id)
+			}
+		}
+	}
+}
+
+// isType reports whether the <id> refers to a type declaration.
+func (v *validator) isType(id uint32) bool {
+	inst, ok := v.definition(id)
+	if !ok {
+		return true // reported as undefined.
+	}
+	info, ok := grammar[inst.Opcode]
+	return ok && strings.HasPrefix(info.name, "Type")
+}
+
+// types checks the type declarations, and that result types refer to types.
+func (v *validator) types() {
+	for i, inst := range v.module.Instructions {
+		operands := v.decoded[i]
+		if operands == nil {
+			continue
+		}
+		if len(operands) > 0 && operands[0].kind == operandResultType && !v.isType(operands[0].words[0]) {
+			v.at(i, "result type %%%d is not a type", operands[0].words[0])
+		}
+		ops := inst.Operands
+		switch inst.Opcode {
+		case OpTypeInt:
+			if width := ops[1]; width != 8 && width != 16 && width != 32 && width != 64 {
+				v.at(i, "unsupported width %d", width)
+			}
+			if ops[2] > 1 {
+				v.at(i, "signedness must be 0 or 1, not %d", ops[2])
+			}
+		case OpTypeFloat:
+			if width := ops[1]; width != 16 && width != 32 && width != 64 {
+				v.at(i, "unsupported width %d", width)
+			}
+		case OpTypeVector:
+			if elem, ok := v.definition(ops[1]); ok && elem.Opcode != OpTypeInt && elem.Opcode != OpTypeFloat && elem.Opcode != OpTypeBool {
+				v.at(i, "component type %%%d is not a scalar", ops[1])
+			}
+			if count := ops[2]; count < 2 || (count > 4 && count != 8 && count != 16) {
+				v.at(i, "unsupported component count %d", count)
+			}
+		case OpTypeMatrix:
+			if column, ok := v.definition(ops[1]); ok {
+				if elem, _ := v.definition(column.Operands[min(1, len(column.Operands)-1)]); column.Opcode != OpTypeVector || elem.Opcode != OpTypeFloat {
+					v.at(i, "column type %%%d is not a floating-point vector", ops[1])
+				}
+			}
+			if count := ops[2]; count < 2 || count > 4 {
+				v.at(i, "unsupported column count %d", count)
+			}
+		case OpTypeArray:
+			if !v.isType(ops[1]) {
+				v.at(i, "element type %%%d is not a type", ops[1])
+			}
+			if length, ok := v.definition(ops[2]); ok {
+				switch length.Opcode {
+				case OpConstant:
+					if len(length.Operands) > 2 && length.Operands[2] == 0 && (len(length.Operands) == 3 || length.Operands[3] == 0) {
+						v.at(i, "length %%%d is zero", ops[2])
+					}
+				case OpSpecConstant, OpSpecConstantOp:
+				default:
+					v.at(i, "length %%%d is not an integer constant", ops[2])
+				}
+			}
+		case OpTypeRuntimeArray, OpTypeSampledImage:
+			if !v.isType(ops[1]) {
+				v.at(i, "element type %%%d is not a type", ops[1])
+			}
+		case OpTypeStruct:
+			for member, id := range ops[1:] {
+				if !v.isType(id) {
+					v.at(i, "member %d type %%%d is not a type", member, id)
+				}
+			}
+		case OpTypePointer:
+			if !v.isType(ops[2]) {
+				v.at(i, "pointee type %%%d is not a type", ops[2])
+			}
+		case OpTypeFunction:
+			for _, id := range ops[1:] {
+				if !v.isType(id) {
+					v.at(i, "%%%d is not a type", id)
+				}
+			}
+		}
+	}
+}
+
+// decorate collects the decorations of the module and checks that they are consistent.
+func (v *validator) decorate() {
+	specIDs := make(map[uint32]uint32)
+	for i, inst := range v.module.Instructions {
+		if v.decoded[i] == nil {
+			continue
+		}
+		switch inst.Opcode {
+		case OpDecorate:
+			id, decoration, params := inst.Operands[0], Decoration(inst.Operands[1]), inst.Operands[2:]
+			if v.decorations[id] == nil {
+				v.decorations[id] = make(decorations)
+			}
+			v.add(i, v.decorations[id], decoration, params, fmt.Sprintf("%%%d", id))
+			target, _ := v.definition(id)
+			switch decoration {
+			case DecorationBlock, DecorationBufferBlock:
+				if target.Opcode != OpTypeStruct {
+					v.at(i, "%v target %%%d is not a structure", enumDecoration.String(uint32(decoration)), id)
+				}
+			case DecorationArrayStride:
+				if target.Opcode != OpTypeArray && target.Opcode != OpTypeRuntimeArray && target.Opcode != OpTypePointer {
+					v.at(i, "ArrayStride target %%%d is not an array or pointer", id)
+				}
+			case DecorationSpecId:
+				switch target.Opcode {
+				case OpSpecConstantTrue, OpSpecConstantFalse, OpSpecConstant:
+				default:
+					v.at(i, "SpecId target %%%d is not a specialization constant", id)
+				}
+				if len(params) > 0 {
+					if other, ok := specIDs[params[0]]; ok && other != id {
+						v.at(i, "SpecId %d is already used by %%%d", params[0], other)
+					}
+					specIDs[params[0]] = id
+				}
+			case DecorationLocation, DecorationComponent:
+				if target.Opcode != OpVariable {
+					v.at(i, "%v target %%%d is not a variable", enumDecoration.String(uint32(decoration)), id)
+				}
+			case DecorationBinding, DecorationDescriptorSet:
+				if target.Opcode != OpVariable {
+					v.at(i, "%v target %%%d is not a variable", enumDecoration.String(uint32(decoration)), id)
+				}
+			}
+		case OpMemberDecorate:
+			id, member, decoration, params := inst.Operands[0], inst.Operands[1], Decoration(inst.Operands[2]), inst.Operands[3:]
+			target, ok := v.definition(id)
+			if ok && target.Opcode != OpTypeStruct {
+				v.at(i, "target %%%d is not a structure", id)
+				continue
+			}
+			if ok && int(member) >= len(target.Operands)-1 {
+				v.at(i, "member %d is out of range for %%%d", member, id)
+				continue
+			}
+			if v.members[id] == nil {
+				v.members[id] = make(map[uint32]decorations)
+			}
+			if v.members[id][member] == nil {
+				v.members[id][member] = make(decorations)
+			}
+			v.add(i, v.members[id][member], decoration, params, fmt.Sprintf("member %d of %%%d", member, id))
+		}
+	}
+	explicit := v.explicitLayout()
+	for id, d := range v.decorations {
+		_, block := d[DecorationBlock]
+		_, bufferBlock := d[DecorationBufferBlock]
+		if block && bufferBlock {
+			v.errorf("%%%d is decorated with both Block and BufferBlock", id)
+		}
+		_, builtin := d[DecorationBuiltIn]
+		_, location := d[DecorationLocation]
+		if builtin && location {
+			v.errorf("%%%d is decorated with both BuiltIn and Location", id)
+		}
+		if (block || bufferBlock) && explicit[id] && !v.builtinBlock(id) {
+			if target, ok := v.definition(id); ok && target.Opcode == OpTypeStruct {
+				for member := range target.Operands[1:] {
+					if _, ok := v.members[id][uint32(member)][DecorationOffset]; !ok {
+						v.errorf("member %d of block %%%d has no Offset", member, id)
+					}
+				}
+			}
+		}
+	}
+	for i, inst := range v.module.Instructions {
+		if inst.Opcode != OpVariable || v.decoded[i] == nil || len(inst.Operands) < 3 {
+			continue
+		}
+		switch StorageClass(inst.Operands[2]) {
+		case StorageClassUniformConstant, StorageClassUniform, StorageClassStorageBuffer:
+			id := inst.Operands[1]
+			_, set := v.decorations[id][DecorationDescriptorSet]
+			_, binding := v.decorations[id][DecorationBinding]
+			if !set || !binding {
+				v.at(i, "resource %%%d must be decorated with both DescriptorSet and Binding", id)
+			}
+		}
+	}
+}
+
+/*
+explicitLayout returns the structures (and arrays of structures) that are pointed to in a storage class with an
+explicit memory layout, as their members must be decorated with Offset. Input and output blocks are decorated
+with Block too, but are laid out by location.
+*/
+func (v *validator) explicitLayout() map[uint32]bool {
+	explicit := make(map[uint32]bool)
+	for i, inst := range v.module.Instructions {
+		if inst.Opcode != OpTypePointer || v.decoded[i] == nil || len(inst.Operands) < 3 {
+			continue
+		}
+		switch StorageClass(inst.Operands[1]) {
+		case StorageClassUniform, StorageClassStorageBuffer, StorageClassPushConstant, StorageClassPhysicalStorageBuffer:
+		default:
+			continue
+		}
+		id := inst.Operands[2]
+		for {
+			t, ok := v.definition(id)
+			if !ok || (t.Opcode != OpTypeArray && t.Opcode != OpTypeRuntimeArray) || len(t.Operands) < 2 {
+				break
+			}
+			id = t.Operands[1]
+		}
+		explicit[id] = true
+	}
+	return explicit
+}
+
+// add a decoration, reporting any conflict with an existing decoration of the same kind.
+func (v *validator) add(i int, d decorations, decoration Decoration, params []uint32, target string) {
+	if existing, ok := d[decoration]; ok && !equal(existing, params) {
+		v.at(i, "%s has conflicting %v decorations", target, enumDecoration.String(uint32(decoration)))
+		return
+	}
+	d[decoration] = params
+}
+
+func equal(a, b []uint32) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// builtinBlock reports whether any member of the structure is a built-in, such as gl_PerVertex.
+func (v *validator) builtinBlock(id uint32) bool {
+	for _, d := range v.members[id] {
+		if _, ok := d[DecorationBuiltIn]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// entryPoints checks entry points, their interfaces and execution modes.
+func (v *validator) entryPoints() {
+	functions := make(map[uint32]bool)
+	names := make(map[string]bool)
+	for i, inst := range v.module.Instructions {
+		operands := v.decoded[i]
+		if operands == nil {
+			continue
+		}
+		switch inst.Opcode {
+		case OpEntryPoint:
+			model, function := ExecutionModel(inst.Operands[0]), inst.Operands[1]
+			name, n := String(inst.Operands[2:])
+			key := fmt.Sprintf("%v %s", model, name)
+			if names[key] {
+				v.at(i, "duplicate %v entry point %q", model, name)
+			}
+			names[key] = true
+			functions[function] = true
+			if target, ok := v.definition(function); ok && target.Opcode != OpFunction {
+				v.at(i, "%%%d is not a function", function)
+			}
+			for _, id := range inst.Operands[2+n:] {
+				target, ok := v.definition(id)
+				if !ok {
+					continue
+				}
+				if target.Opcode != OpVariable {
+					v.at(i, "interface %%%d is not a variable", id)
+					continue
+				}
+				if model <= ExecutionModelGLCompute {
+					v.location(i, target)
+				}
+			}
+		case OpExecutionMode, OpExecutionModeId:
+			if !functions[inst.Operands[0]] {
+				v.at(i, "%%%d is not an entry point", inst.Operands[0])
+			}
+		}
+	}
+}
+
+// location checks that a non-built-in input or output variable has a location.
+func (v *validator) location(i int, variable Instruction) {
+	id := variable.Operands[1]
+	if class := StorageClass(variable.Operands[2]); class != StorageClassInput && class != StorageClassOutput {
+		return
+	}
+	d := v.decorations[id]
+	if _, ok := d[DecorationLocation]; ok {
+		return
+	}
+	if _, ok := d[DecorationBuiltIn]; ok {
+		return
+	}
+	// a structure, or array of structures, may have a location or built-in on each of its members.
+	pointer, _ := v.definition(variable.Operands[0])
+	if pointer.Opcode != OpTypePointer {
+		return
+	}
+	t, _ := v.definition(pointer.Operands[2])
+	for t.Opcode == OpTypeArray || t.Opcode == OpTypeRuntimeArray {
+		t, _ = v.definition(t.Operands[1])
+	}
+	if t.Opcode == OpTypeStruct {
+		if v.builtinBlock(t.Operands[0]) {
+			return
+		}
+		complete := len(t.Operands) > 1
+		for member := range t.Operands[1:] {
+			if _, ok := v.members[t.Operands[0]][uint32(member)][DecorationLocation]; !ok {
+				complete = false
+			}
+		}
+		if complete {
+			return
+		}
+	}
+	v.at(i, "interface %%%d has no Location", id)
+}
+
+// interfaces checks the reflected interfaces of the entry points, once the module is known to be well-formed.
+func (v *validator) interfaces() {
+	reflection, err := v.module.Reflect()
+	if err != nil {
+		v.errs = append(v.errs, err)
+		return
+	}
+	for _, ep := range reflection.EntryPoints {
+		if ep.Model != ExecutionModelFragment {
+			continue
+		}
+		for _, input := range ep.Inputs {
+			if scalar := input.Type.Scalar(); scalar != nil && (scalar.Kind == KindInt || scalar.Kind == KindBool || scalar.Width == 64) && !input.Flat {
+				v.errorf("fragment input location %d (%v %s) must be decorated with Flat", input.Location, input.Type, input.Name)
+			}
+		}
+	}
+}
+
+/*
+CheckInterface returns an error for each input of the consumer that is not written by an output of the
+producer with a compatible type, for example:
+
+	Fragment input location 2 (vec2 uv) is not written by the Vertex stage
+
+Outputs may have more components than the inputs that read them, but the component types must match.
+*/
+func CheckInterface(producer, consumer *EntryPoint) error {
+	return errors.Join(checkInterface(producer, consumer)...)
+}
+
+func checkInterface(producer, consumer *EntryPoint) []error {
+	type slot struct{ location, component uint32 }
+	outputs := make(map[slot]Location, len(producer.Outputs))
+	for _, output := range producer.Outputs {
+		outputs[slot{output.Location, output.Component}] = output
+	}
+	var errs []error
+	for _, input := range consumer.Inputs {
+		output, ok := outputs[slot{input.Location, input.Component}]
+		if !ok {
+			errs = append(errs, fmt.Errorf("%v input location %d (%v %s) is not written by the %v stage",
+				consumer.Model, input.Location, input.Type, input.Name, producer.Model))
+			continue
+		}
+		if !compatible(output.Type, input.Type) {
+			errs = append(errs, fmt.Errorf("%v input location %d (%v %s) does not match %v output (%v %s)",
+				consumer.Model, input.Location, input.Type, input.Name, producer.Model, output.Type, output.Name))
+		}
+	}
+	return errs
+}
+
+// compatible reports whether an output of the given type can be read by an input of the given type.
+func compatible(output, input *Type) bool {
+	components := func(t *Type) int {
+		if t.Kind == KindVector {
+			return t.Length
+		}
+		return 1
+	}
+	if (output.IsScalar() || output.Kind == KindVector) && (input.IsScalar() || input.Kind == KindVector) {
+		out, in := output.Scalar(), input.Scalar()
+		return out.Kind == in.Kind && out.Width == in.Width && out.Signed == in.Signed && components(output) >= components(input)
+	}
+	return output.String() == input.String()
+}
+
+/*
+Validate each stage of the bundle with [Module.Validate] and, if the bundle has no tessellation or geometry
+stages, check that the outputs of the vertex stage feed the inputs of the fragment stage with
+[CheckInterface].
+*/
+func (b Bundle) Validate() error {
+	models := make([]ExecutionModel, 0, len(b))
+	for model := range b {
+		models = append(models, model)
+	}
+	sort.Slice(models, func(i, j int) bool { return models[i] < models[j] })
+	var errs []error
+	for _, model := range models {
+		module, err := ParseWords(b[model])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("spirv: %v stage: %w", model, err))
+			continue
+		}
+		for _, err := range module.validate() {
+			errs = append(errs, fmt.Errorf("spirv: %v stage: %w", model, err))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	for _, model := range []ExecutionModel{ExecutionModelTessellationControl, ExecutionModelTessellationEvaluation, ExecutionModelGeometry} {
+		if _, ok := b[model]; ok {
+			return nil
+		}
+	}
+	if _, ok := b[ExecutionModelVertex]; !ok {
+		return nil
+	}
+	if _, ok := b[ExecutionModelFragment]; !ok {
+		return nil
+	}
+	reflections, err := b.Reflect()
+	if err != nil {
+		return err
+	}
+	vertex := reflections[ExecutionModelVertex].EntryPoint(ExecutionModelVertex)
+	fragment := reflections[ExecutionModelFragment].EntryPoint(ExecutionModelFragment)
+	if vertex == nil || fragment == nil {
+		return nil
+	}
+	for _, err := range checkInterface(vertex, fragment) {
+		errs = append(errs, fmt.Errorf("spirv: %w", err))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,28 @@
+package spirv
+
+import "testing"
+
+func TestValidateSpecConstantOp(t *testing.T) {
+	if err := computeModule().Validate(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestValidateIOBlock(t *testing.T) {
+	if err := blockModule().Validate(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestValidateBlockOffsets(t *testing.T) {
+	m := blockModule()
+	m.Bound = 13
+	variables := []Instruction{
+		{OpTypePointer, []uint32{11, 9, 7}}, // PushConstant
+		{OpVariable, []uint32{11, 12, 9}},
+	}
+	m.Instructions = append(m.Instructions[:16], append(variables, m.Instructions[16:]...)...)
+	if err := m.Validate(); err == nil {
+		t.Error("expected an error for a push constant block without offsets")
+	}
+}
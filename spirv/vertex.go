@@ -0,0 +1,116 @@
+package spirv
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"grow.graphics/rd"
+)
+
+/*
+CheckVertexFormat returns an error for each of the inputs of the entry point that are not fed by one of the
+given attributes with a compatible [rd.DataFormat], such as:
+
+	location 3 expects vec4 float but VertexFormat supplies R8G8_UINT
+
+Formats with more or fewer components than the input are permitted, as the rendering device discards extra
+components and fills in missing ones, however the numeric type (float, int, uint or double) must match.
+*/
+func (ep *EntryPoint) CheckVertexFormat(attributes []rd.VertexAttribute) error {
+	supplied := make(map[uint32]rd.DataFormat, len(attributes))
+	for _, attribute := range attributes {
+		supplied[uint32(attribute.Location)] = attribute.Format
+	}
+	var errs []error
+	for _, input := range ep.Inputs {
+		location := input.Location
+		for _, slot := range locationsOf(input.Type) {
+			expects := slot.String() + " " + numericType(slot)
+			format, ok := supplied[location]
+			switch {
+			case !ok:
+				errs = append(errs, fmt.Errorf("location %d expects %s but VertexFormat does not supply it", location, expects))
+			case vertexNumericType(format) != numericType(slot):
+				errs = append(errs, fmt.Errorf("location %d expects %s but VertexFormat supplies %v", location, expects, format))
+			}
+			location += locationWidth(slot)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// locationWidth returns the number of locations occupied by a scalar or vector, which is two for 64-bit vectors
+// with more than two components (dvec3 and dvec4), and one otherwise.
+func locationWidth(t *Type) uint32 {
+	if scalar := t.Scalar(); t.Kind == KindVector && scalar != nil && scalar.Width == 64 && t.Length > 2 {
+		return 2
+	}
+	return 1
+}
+
+// locationCount returns the number of locations occupied by the given type.
+func locationCount(t *Type) uint32 {
+	var count uint32
+	for _, slot := range locationsOf(t) {
+		count += locationWidth(slot)
+	}
+	return count
+}
+
+// locationsOf returns the type consumed at each location occupied by the given type.
+func locationsOf(t *Type) []*Type {
+	switch t.Kind {
+	case KindMatrix:
+		slots := make([]*Type, t.Length)
+		for i := range slots {
+			slots[i] = t.Elem
+		}
+		return slots
+	case KindArray:
+		var slots []*Type
+		for i := 0; i < t.Length; i++ {
+			slots = append(slots, locationsOf(t.Elem)...)
+		}
+		return slots
+	default:
+		return []*Type{t}
+	}
+}
+
+// numericType returns the numeric type of a shader input: float, double, int or uint.
+func numericType(t *Type) string {
+	scalar := t.Scalar()
+	switch {
+	case scalar == nil:
+		return "unknown"
+	case scalar.Kind == KindFloat && scalar.Width == 64:
+		return "double"
+	case scalar.Kind == KindFloat:
+		return "float"
+	case scalar.Kind == KindInt && scalar.Signed:
+		return "int"
+	case scalar.Kind == KindInt:
+		return "uint"
+	default:
+		return "bool"
+	}
+}
+
+// vertexNumericType returns the numeric type that the given format is read as by a vertex shader.
+func vertexNumericType(format rd.DataFormat) string {
+	name := format.String()
+	switch {
+	case strings.HasPrefix(name, "R64") && strings.Contains(name, "_SFLOAT"):
+		return "double"
+	case strings.Contains(name, "_UINT"):
+		return "uint"
+	case strings.Contains(name, "_SINT"):
+		return "int"
+	case strings.Contains(name, "NORM"), strings.Contains(name, "SCALED"),
+		strings.Contains(name, "FLOAT"), strings.Contains(name, "_SRGB"):
+		return "float"
+	default:
+		return "unknown"
+	}
+}
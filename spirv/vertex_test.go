@@ -0,0 +1,27 @@
+package spirv
+
+import (
+	"testing"
+
+	"grow.graphics/rd"
+)
+
+func TestCheckVertexFormatDouble(t *testing.T) {
+	var (
+		double = &Type{Kind: KindFloat, Width: 64}
+		dvec4  = &Type{Kind: KindVector, Elem: double, Length: 4}
+		float  = &Type{Kind: KindFloat, Width: 32}
+	)
+	ep := EntryPoint{Inputs: []Location{
+		{Name: "position", Location: 0, Type: &Type{Kind: KindMatrix, Elem: dvec4, Length: 2}},
+		{Name: "weight", Location: 4, Type: float},
+	}}
+	err := ep.CheckVertexFormat([]rd.VertexAttribute{
+		{Location: 0, Format: rd.DataFormat_R64G64B64A64_SFLOAT},
+		{Location: 2, Format: rd.DataFormat_R64G64B64A64_SFLOAT},
+		{Location: 4, Format: rd.DataFormat_R32_SFLOAT},
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
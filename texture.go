@@ -1,7 +1,10 @@
 package rd
 
 import (
+	"fmt"
 	"io"
+	"sort"
+	"strings"
 
 	"grow.graphics/uc"
 )
@@ -68,7 +71,104 @@ type TextureFormat struct {
 	Usage       TextureUsage   // The texture's usage bits, which determine what can be done using the texture.
 	Width       int            // The texture's width (in pixels).
 
-	ShareableFormats map[DataFormat]struct{} // The shareable formats for this texture.
+	ShareableFormats map[DataFormat]struct{} // The shareable formats for this texture, see [TextureFormat.ShareWith].
+}
+
+/*
+ShareWith adds the format of the texture, along with the FormatOverride of each of the given views, to
+the ShareableFormats of the texture, so that these views can later be passed to [Interface.SharedTexture].
+Returns an error (and leaves ShareableFormats unchanged) if any of the views cannot be used to reinterpret
+the texture's format, see [DataFormat.CompatibleWith].
+*/
+func (format *TextureFormat) ShareWith(views ...TextureView) error {
+	for _, view := range views {
+		if view.FormatOverride == DataFormatDefault {
+			continue
+		}
+		if !format.Format.CompatibleWith(view.FormatOverride) {
+			return fmt.Errorf("rd: texture format %v cannot be viewed as %v", format.Format, view.FormatOverride)
+		}
+	}
+	if format.ShareableFormats == nil {
+		format.ShareableFormats = make(map[DataFormat]struct{})
+	}
+	format.ShareableFormats[format.Format] = struct{}{}
+	for _, view := range views {
+		if view.FormatOverride != DataFormatDefault {
+			format.ShareableFormats[view.FormatOverride] = struct{}{}
+		}
+	}
+	return nil
+}
+
+// ValidateView returns an error if the view's FormatOverride cannot be used to share a texture with this format.
+func (format TextureFormat) ValidateView(view TextureView) error {
+	override := view.FormatOverride
+	if override == DataFormatDefault || override == format.Format {
+		return nil
+	}
+	if !format.Format.CompatibleWith(override) {
+		return fmt.Errorf("rd: texture format %v cannot be viewed as %v", format.Format, override)
+	}
+	if _, ok := format.ShareableFormats[override]; !ok {
+		return fmt.Errorf("rd: %v is not one of the shareable formats of the texture", override)
+	}
+	return nil
+}
+
+// Size returns the estimated number of bytes of memory used by a texture with this format, including its
+// mipmaps, layers and samples, with block-compressed formats rounded up to whole blocks. Returns zero for
+// multi-planar formats, see [DataFormat.Block].
+func (format TextureFormat) Size() int {
+	bw, bh, size := format.Format.Block()
+	if size == 0 {
+		return 0
+	}
+	var (
+		width  = max(format.Width, 1)
+		height = max(format.Height, 1)
+		depth  = max(format.Depth, 1)
+		blocks int
+	)
+	for i := 0; i < max(format.Mipmaps, 1); i++ {
+		blocks += (max(width>>i, 1) + bw - 1) / bw * ((max(height>>i, 1) + bh - 1) / bh) * max(depth>>i, 1)
+	}
+	return blocks * max(format.ArrayLayers, 1) * size << format.Samples
+}
+
+// TextureKey is a comparable identity of a [TextureFormat] and [TextureView], such that textures with equal keys
+// can be used interchangeably. ShareableFormats are compared by their contents rather than by the identity of
+// the map.
+type TextureKey struct {
+	arrayLayers, depth, height, mipmaps, width int
+	format                                     DataFormat
+	samples                                    TextureSamples
+	textureType                                TextureType
+	usage                                      TextureUsage
+	shareable                                  string
+	view                                       TextureView
+}
+
+// Key returns the [TextureKey] of a texture with this format, viewed with the given view.
+func (format TextureFormat) Key(view TextureView) TextureKey {
+	shareable := make([]string, 0, len(format.ShareableFormats))
+	for f := range format.ShareableFormats {
+		shareable = append(shareable, f.String())
+	}
+	sort.Strings(shareable)
+	return TextureKey{
+		arrayLayers: format.ArrayLayers,
+		depth:       format.Depth,
+		height:      format.Height,
+		mipmaps:     format.Mipmaps,
+		width:       format.Width,
+		format:      format.Format,
+		samples:     format.Samples,
+		textureType: format.TextureType,
+		usage:       format.Usage,
+		shareable:   strings.Join(shareable, ","),
+		view:        view,
+	}
 }
 
 // TextureType for a texture.
@@ -102,7 +202,7 @@ const (
 
 // TextureView for a texture.
 type TextureView struct {
-	FormatOverride DataFormat // Optional override for the data format to return sampled values in.
+	FormatOverride DataFormat // Optional override for the data format to return sampled values in, [DataFormatDefault] for none.
 	SwizzleAlpha   Swizzle    // The channel to sample when sampling the alpha channel.
 	SwizzleBlue    Swizzle    // The channel to sample when sampling the blue channel.
 	SwizzleGreen   Swizzle    // The channel to sample when sampling the green channel.
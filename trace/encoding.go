@@ -0,0 +1,531 @@
+package trace
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"slices"
+	"sort"
+
+	"grow.graphics/rd"
+	"grow.graphics/rd/internal/unwrap"
+)
+
+const (
+	tagNil byte = iota
+	tagBool
+	tagInt
+	tagUint
+	tagFloat
+	tagString
+	tagBytes
+	tagList
+	tagMap
+	tagRef
+	tagTyped
+)
+
+// types that can be held by an interface within a trace, by name.
+var types = make(map[string]reflect.Type)
+
+func init() {
+	for _, value := range []any{
+		false, int(0), int8(0), int16(0), int32(0), int64(0), uint(0), uint8(0), uint16(0), uint32(0), uint64(0),
+		float32(0), float64(0), "",
+		rd.SamplerWithTexture{}, rd.SamplerWithTextureBuffer{}, rd.InputAttachment{},
+	} {
+		rtype := reflect.TypeOf(value)
+		types[rtype.String()] = rtype
+	}
+}
+
+// object created by a recorded call, that can be referred to by its ID.
+type object interface {
+	traceID() uint64
+}
+
+// objectOf returns the object that v refers to, looking through uniform and storage buffers to the wrapper of
+// their buffer.
+func objectOf(v any) (object, bool) {
+	if b, ok := v.(*unwrap.VariableBuffer); ok {
+		v = b.Buffer
+	}
+	obj, ok := v.(object)
+	return obj, ok
+}
+
+// encoder writes calls to a trace.
+type encoder struct {
+	w   *bufio.Writer
+	buf []byte
+}
+
+func (e *encoder) uvarint(v uint64) { e.buf = binary.AppendUvarint(e.buf, v) }
+
+func (e *encoder) call(c Call) {
+	e.buf = e.buf[:0]
+	e.uvarint(uint64(c.Method))
+	e.uvarint(c.Object)
+	e.uvarint(c.Result)
+	e.buf = append(e.buf, tagList)
+	e.uvarint(uint64(len(c.Args)))
+	for _, arg := range c.Args {
+		e.value(reflect.ValueOf(arg))
+	}
+	e.value(reflect.ValueOf(c.Return))
+	e.w.Write(e.buf)
+}
+
+// value encodes a Go value, objects created by the recorder are encoded as references.
+func (e *encoder) value(v reflect.Value) {
+	if !v.IsValid() {
+		e.buf = append(e.buf, tagNil)
+		return
+	}
+	if v.Type() == reflect.TypeOf(Ref(0)) {
+		e.buf = append(e.buf, tagRef)
+		e.uvarint(v.Uint())
+		return
+	}
+	if v.CanInterface() {
+		if obj, ok := objectOf(v.Interface()); ok && v.Kind() != reflect.Interface {
+			e.buf = append(e.buf, tagRef)
+			e.uvarint(obj.traceID())
+			return
+		}
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		e.buf = append(e.buf, tagBool, 0)
+		if v.Bool() {
+			e.buf[len(e.buf)-1] = 1
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		e.buf = append(e.buf, tagInt)
+		e.buf = binary.AppendVarint(e.buf, v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		e.buf = append(e.buf, tagUint)
+		e.uvarint(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		e.buf = append(e.buf, tagFloat)
+		e.buf = binary.LittleEndian.AppendUint64(e.buf, math.Float64bits(v.Float()))
+	case reflect.String:
+		e.buf = append(e.buf, tagString)
+		e.uvarint(uint64(v.Len()))
+		e.buf = append(e.buf, v.String()...)
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+			e.buf = append(e.buf, tagBytes)
+			e.uvarint(uint64(v.Len()))
+			e.buf = append(e.buf, v.Bytes()...)
+			return
+		}
+		e.buf = append(e.buf, tagList)
+		e.uvarint(uint64(v.Len()))
+		for i := 0; i < v.Len(); i++ {
+			e.value(v.Index(i))
+		}
+	case reflect.Struct:
+		fields := exportedFields(v.Type())
+		e.buf = append(e.buf, tagList)
+		e.uvarint(uint64(len(fields)))
+		for _, i := range fields {
+			e.value(v.Field(i))
+		}
+	case reflect.Map:
+		keys := v.MapKeys()
+		switch v.Type().Key().Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			sort.Slice(keys, func(i, j int) bool { return keys[i].Int() < keys[j].Int() })
+		default:
+			sort.Slice(keys, func(i, j int) bool { return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j]) })
+		}
+		e.buf = append(e.buf, tagMap)
+		e.uvarint(uint64(len(keys)))
+		for _, key := range keys {
+			e.value(key)
+			e.value(v.MapIndex(key))
+		}
+	case reflect.Pointer:
+		if v.IsNil() {
+			e.buf = append(e.buf, tagNil)
+			return
+		}
+		e.value(v.Elem())
+	case reflect.Interface:
+		if v.IsNil() {
+			e.buf = append(e.buf, tagNil)
+			return
+		}
+		elem := v.Elem()
+		if obj, ok := objectOf(elem.Interface()); ok {
+			e.buf = append(e.buf, tagRef)
+			e.uvarint(obj.traceID())
+			return
+		}
+		if _, ok := types[elem.Type().String()]; !ok {
+			e.buf = append(e.buf, tagNil) // objects that were not created through the recorder.
+			return
+		}
+		e.buf = append(e.buf, tagTyped, tagString)
+		e.uvarint(uint64(len(elem.Type().String())))
+		e.buf = append(e.buf, elem.Type().String()...)
+		e.value(elem)
+	default:
+		e.buf = append(e.buf, tagNil)
+	}
+}
+
+// exportedFields returns the indices of the exported fields of the struct type, which are the fields that
+// are captured in a trace.
+func exportedFields(rtype reflect.Type) []int {
+	var fields []int
+	for i := 0; i < rtype.NumField(); i++ {
+		if rtype.Field(i).IsExported() {
+			fields = append(fields, i)
+		}
+	}
+	return fields
+}
+
+/*
+Reader reads the calls from a trace.
+
+	reader, err := trace.NewReader(file)
+	if err != nil {
+		return err
+	}
+	for {
+		call, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Println(call)
+	}
+*/
+type Reader struct {
+	r       *bufio.Reader
+	version uint64
+}
+
+// NewReader reads the header of the trace, returning an error if it is not a trace, or if it was written with
+// a newer version of the format.
+func NewReader(r io.Reader) (*Reader, error) {
+	reader := &Reader{r: bufio.NewReader(r)}
+	header := make([]byte, len(magic))
+	if _, err := io.ReadFull(reader.r, header); err != nil || string(header) != magic {
+		return nil, errors.New("trace: not a trace file")
+	}
+	version, err := binary.ReadUvarint(reader.r)
+	if err != nil {
+		return nil, fmt.Errorf("trace: reading version: %w", err)
+	}
+	if version == 0 || version > Version {
+		return nil, fmt.Errorf("trace: unsupported version %d (expected at most %d)", version, Version)
+	}
+	reader.version = version
+	return reader, nil
+}
+
+// Version of the format that the trace was written with.
+func (r *Reader) Version() int { return int(r.version) }
+
+// Next returns the next call in the trace, or [io.EOF] once all of the calls have been read.
+func (r *Reader) Next() (Call, error) {
+	method, err := binary.ReadUvarint(r.r)
+	if err == io.EOF {
+		return Call{}, io.EOF
+	}
+	var c Call
+	c.Method = Method(method)
+	if err == nil {
+		c.Object, err = binary.ReadUvarint(r.r)
+	}
+	if err == nil {
+		c.Result, err = binary.ReadUvarint(r.r)
+	}
+	var args any
+	if err == nil {
+		args, err = r.value(0)
+	}
+	if err == nil {
+		c.Return, err = r.value(0)
+	}
+	if err != nil {
+		return Call{}, fmt.Errorf("trace: reading %v: %w", c.Method, noEOF(err))
+	}
+	list, ok := args.([]any)
+	if !ok && args != nil {
+		return Call{}, fmt.Errorf("trace: reading %v: arguments are not a list", c.Method)
+	}
+	c.Args = list
+	return c, nil
+}
+
+// noEOF converts an [io.EOF] in the middle of a call into an [io.ErrUnexpectedEOF].
+func noEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+func (r *Reader) length() (int, error) {
+	n, err := binary.ReadUvarint(r.r)
+	if err != nil {
+		return 0, err
+	}
+	if n > math.MaxInt32 {
+		return 0, fmt.Errorf("length %d is too large", n)
+	}
+	return int(n), nil
+}
+
+func (r *Reader) bytes() ([]byte, error) {
+	n, err := r.length()
+	if err != nil {
+		return nil, err
+	}
+	// read in chunks, so that a corrupt length fails at the end of the trace, rather than
+	// allocating up to 2GB upfront.
+	data := make([]byte, 0, min(n, readChunk))
+	for len(data) < n {
+		chunk := min(n-len(data), readChunk)
+		data = slices.Grow(data, chunk)[:len(data)+chunk]
+		if _, err := io.ReadFull(r.r, data[len(data)-chunk:]); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+const (
+	readChunk = 64 << 10 // bytes read at a time for strings and byte slices.
+	maxDepth  = 100      // of nested lists, maps and typed values.
+)
+
+// value reads a value in its generic form, see [Call], at the given depth of nesting.
+func (r *Reader) value(depth int) (any, error) {
+	if depth > maxDepth {
+		return nil, fmt.Errorf("values are nested more than %d deep", maxDepth)
+	}
+	tag, err := r.r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	switch tag {
+	case tagNil:
+		return nil, nil
+	case tagBool:
+		b, err := r.r.ReadByte()
+		return b != 0, err
+	case tagInt:
+		return binary.ReadVarint(r.r)
+	case tagUint:
+		return binary.ReadUvarint(r.r)
+	case tagFloat:
+		var bits [8]byte
+		if _, err := io.ReadFull(r.r, bits[:]); err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.LittleEndian.Uint64(bits[:])), nil
+	case tagString:
+		data, err := r.bytes()
+		return string(data), err
+	case tagBytes:
+		return r.bytes()
+	case tagList:
+		n, err := r.length()
+		if err != nil {
+			return nil, err
+		}
+		list := make([]any, 0, min(n, 1024))
+		for i := 0; i < n; i++ {
+			elem, err := r.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, elem)
+		}
+		return list, nil
+	case tagMap:
+		n, err := r.length()
+		if err != nil {
+			return nil, err
+		}
+		entries := make([]Entry, 0, min(n, 1024))
+		for i := 0; i < n; i++ {
+			key, err := r.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			value, err := r.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, Entry{Key: key, Value: value})
+		}
+		return entries, nil
+	case tagRef:
+		id, err := binary.ReadUvarint(r.r)
+		return Ref(id), err
+	case tagTyped:
+		name, err := r.value(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := name.(string); !ok {
+			return nil, errors.New("typed value without a type name")
+		}
+		value, err := r.value(depth + 1)
+		return Typed{Type: name.(string), Value: value}, err
+	default:
+		return nil, fmt.Errorf("unknown tag %d", tag)
+	}
+}
+
+// assign a value in its generic form to dst, resolving references with resolve.
+func assign(dst reflect.Value, value any, resolve func(Ref) (any, error)) error {
+	if value == nil {
+		dst.SetZero()
+		return nil
+	}
+	if ref, ok := value.(Ref); ok {
+		obj, err := resolve(ref)
+		if err != nil {
+			return err
+		}
+		if obj == nil {
+			dst.SetZero()
+			return nil
+		}
+		v := reflect.ValueOf(obj)
+		if !v.Type().AssignableTo(dst.Type()) {
+			return fmt.Errorf("%v is a %T, not a %v", ref, obj, dst.Type())
+		}
+		dst.Set(v)
+		return nil
+	}
+	mismatch := func() error { return fmt.Errorf("cannot assign %s to %v", Format(value), dst.Type()) }
+	switch dst.Kind() {
+	case reflect.Bool:
+		b, ok := value.(bool)
+		if !ok {
+			return mismatch()
+		}
+		dst.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, ok := value.(int64)
+		if !ok {
+			return mismatch()
+		}
+		dst.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, ok := value.(uint64)
+		if !ok {
+			return mismatch()
+		}
+		dst.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, ok := value.(float64)
+		if !ok {
+			return mismatch()
+		}
+		dst.SetFloat(f)
+	case reflect.String:
+		s, ok := value.(string)
+		if !ok {
+			return mismatch()
+		}
+		dst.SetString(s)
+	case reflect.Slice:
+		if data, ok := value.([]byte); ok && dst.Type().Elem().Kind() == reflect.Uint8 {
+			dst.SetBytes(data)
+			return nil
+		}
+		list, ok := value.([]any)
+		if !ok {
+			return mismatch()
+		}
+		dst.Set(reflect.MakeSlice(dst.Type(), len(list), len(list)))
+		for i, elem := range list {
+			if err := assign(dst.Index(i), elem, resolve); err != nil {
+				return err
+			}
+		}
+	case reflect.Array:
+		list, ok := value.([]any)
+		if !ok || len(list) != dst.Len() {
+			return mismatch()
+		}
+		for i, elem := range list {
+			if err := assign(dst.Index(i), elem, resolve); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		list, ok := value.([]any)
+		if !ok {
+			return mismatch()
+		}
+		fields := exportedFields(dst.Type())
+		if len(list) != len(fields) {
+			return mismatch()
+		}
+		for i, field := range fields {
+			if err := assign(dst.Field(field), list[i], resolve); err != nil {
+				return fmt.Errorf("%v.%s: %w", dst.Type(), dst.Type().Field(field).Name, err)
+			}
+		}
+	case reflect.Map:
+		entries, ok := value.([]Entry)
+		if !ok {
+			return mismatch()
+		}
+		dst.Set(reflect.MakeMapWithSize(dst.Type(), len(entries)))
+		for _, entry := range entries {
+			key := reflect.New(dst.Type().Key()).Elem()
+			elem := reflect.New(dst.Type().Elem()).Elem()
+			if err := assign(key, entry.Key, resolve); err != nil {
+				return err
+			}
+			if err := assign(elem, entry.Value, resolve); err != nil {
+				return err
+			}
+			dst.SetMapIndex(key, elem)
+		}
+	case reflect.Pointer:
+		elem := reflect.New(dst.Type().Elem())
+		if err := assign(elem.Elem(), value, resolve); err != nil {
+			return err
+		}
+		dst.Set(elem)
+	case reflect.Interface:
+		typed, ok := value.(Typed)
+		if !ok {
+			return mismatch()
+		}
+		rtype, ok := types[typed.Type]
+		if !ok {
+			return fmt.Errorf("unknown type %s", typed.Type)
+		}
+		elem := reflect.New(rtype).Elem()
+		if err := assign(elem, typed.Value, resolve); err != nil {
+			return err
+		}
+		if !rtype.AssignableTo(dst.Type()) {
+			return mismatch()
+		}
+		dst.Set(elem)
+	default:
+		return mismatch()
+	}
+	return nil
+}
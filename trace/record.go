@@ -0,0 +1,707 @@
+package trace
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"grow.graphics/rd"
+	"grow.graphics/rd/internal/unwrap"
+	"grow.graphics/uc"
+	"grow.graphics/xy"
+)
+
+// Recorder writes the calls made to a wrapped device into a trace.
+type Recorder struct {
+	mutex    sync.Mutex
+	encoder  encoder
+	binaries map[[sha256.Size]byte]uint64 // ID of the CompileSPIRV call that produced each binary.
+
+	ids atomic.Uint64
+}
+
+/*
+Wrap the device, such that each call made to it (and to the resources, lists and local devices that it creates)
+is written to w as a trace. Calls are buffered, see [Recorder.Flush]. The wrapped device must not be used
+after the recorder has been flushed for the last time.
+*/
+func Wrap(device rd.Interface, w io.Writer) (rd.Interface, *Recorder) {
+	r := &Recorder{
+		encoder:  encoder{w: bufio.NewWriter(w)},
+		binaries: make(map[[sha256.Size]byte]uint64),
+	}
+	r.encoder.w.WriteString(magic)
+	r.encoder.w.Write(binary.AppendUvarint(nil, Version))
+	return &wrapper{Interface: device, traced: traced{recorder: r}}, r
+}
+
+// Frame marks the end of a frame, such that tools can break down a trace by frame, and flushes the trace.
+func (r *Recorder) Frame() error {
+	r.record(Call{Method: MethodFrame})
+	return r.Flush()
+}
+
+// Flush writes any buffered calls, returning the first error encountered while writing the trace.
+func (r *Recorder) Flush() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.encoder.w.Flush()
+}
+
+func (r *Recorder) record(c Call) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.encoder.call(c)
+}
+
+// binary returns the argument that identifies a shader binary, a reference to the call that compiled it,
+// if it was compiled by the recorded device, otherwise its data.
+func (r *Recorder) binary(data []byte) any {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if id, ok := r.binaries[sha256.Sum256(data)]; ok {
+		return Ref(id)
+	}
+	return data
+}
+
+// traced object, created by a recorded call.
+type traced struct {
+	recorder *Recorder
+	id       uint64
+}
+
+func (t traced) traceID() uint64 { return t.id }
+
+// record a call to a method of the object.
+func (t traced) record(method Method, args ...any) {
+	t.recorder.record(Call{Method: method, Object: t.id, Args: args})
+}
+
+// query records a call to a method of the object, along with its result.
+func (t traced) query(method Method, result any, args ...any) {
+	t.recorder.record(Call{Method: method, Object: t.id, Args: args, Return: result})
+}
+
+// create records a call to a method of the object, that created a new object.
+func (t traced) create(method Method, args ...any) traced {
+	created := traced{recorder: t.recorder, id: t.recorder.ids.Add(1)}
+	t.recorder.record(Call{Method: method, Object: t.id, Result: created.id, Args: args})
+	return created
+}
+
+// frame is the form in which an [rd.Frame] is captured, with its Color and Depth functions evaluated.
+type frame struct {
+	Buffer  rd.Framebuffer
+	Color   *frameActions
+	Depth   *frameActions
+	Clear   rd.Clear
+	Region  xy.Rect2
+	Storage []rd.Texture
+}
+
+type frameActions struct {
+	Start rd.FrameStart
+	Ended rd.FrameEnded
+}
+
+func (a *frameActions) fn() func() (rd.FrameStart, rd.FrameEnded) {
+	if a == nil {
+		return nil
+	}
+	start, ended := a.Start, a.Ended
+	return func() (rd.FrameStart, rd.FrameEnded) { return start, ended }
+}
+
+func captureFrame(f rd.Frame) frame {
+	captured := frame{Buffer: f.Buffer, Clear: f.Clear, Region: f.Region, Storage: f.Storage}
+	if f.Color != nil {
+		start, ended := f.Color()
+		captured.Color = &frameActions{Start: start, Ended: ended}
+	}
+	if f.Depth != nil {
+		start, ended := f.Depth()
+		captured.Depth = &frameActions{Start: start, Ended: ended}
+	}
+	return captured
+}
+
+// frame returns the captured frame as an [rd.Frame].
+func (f frame) frame() rd.Frame {
+	return rd.Frame{
+		Buffer:  f.Buffer,
+		Color:   f.Color.fn(),
+		Depth:   f.Depth.fn(),
+		Clear:   f.Clear,
+		Region:  f.Region,
+		Storage: f.Storage,
+	}
+}
+
+func (f frame) unwrap() frame {
+	f.Buffer, f.Storage = unwrap.Value(f.Buffer), unwrap.Slice(f.Storage)
+	return f
+}
+
+// stages is the form in which an [rd.SPIRV] is captured.
+type stages struct {
+	Compute, Fragment, TesselationControl, TesselationEvaluation, Vertex []byte
+}
+
+func captureStages(source rd.SPIRV) stages {
+	return stages{
+		Compute:               source.Compute(),
+		Fragment:              source.Fragment(),
+		TesselationControl:    source.TesselationControl(),
+		TesselationEvaluation: source.TesselationEvaluation(),
+		Vertex:                source.Vertex(),
+	}
+}
+
+// indices encodes index data as little-endian bytes, which is more compact within a trace.
+func indices[T uint16 | uint32](data []T) []byte {
+	var encoded bytes.Buffer
+	binary.Write(&encoded, binary.LittleEndian, data)
+	return encoded.Bytes()
+}
+
+type wrapper struct {
+	rd.Interface
+	traced
+}
+
+func (w *wrapper) Barrier(from, upto rd.Barrier) {
+	w.record(MethodBarrier, from, upto)
+	w.Interface.Barrier(from, upto)
+}
+
+func (w *wrapper) BarrierFull() {
+	w.record(MethodBarrierFull)
+	w.Interface.BarrierFull()
+}
+
+func (w *wrapper) CaptureTimestamp(name string) rd.Timestamp {
+	w.record(MethodCaptureTimestamp, name)
+	return w.Interface.CaptureTimestamp(name)
+}
+
+func (w *wrapper) CompileBinary(data []byte) rd.Shader {
+	s := w.Interface.CompileBinary(data)
+	return &shader{Shader: s, traced: w.create(MethodCompileBinary, w.recorder.binary(data))}
+}
+
+func (w *wrapper) CompileSPIRV(name string, source rd.SPIRV) []byte {
+	source = unwrap.Value(source)
+	compiled := w.Interface.CompileSPIRV(name, source)
+	id := w.create(MethodCompileSPIRV, name, captureStages(source)).id
+	w.recorder.mutex.Lock()
+	w.recorder.binaries[sha256.Sum256(compiled)] = id
+	w.recorder.mutex.Unlock()
+	return compiled
+}
+
+func (w *wrapper) CompileSource(cache bool, source rd.ShaderSource) rd.SPIRV {
+	compiled := w.Interface.CompileSource(cache, source)
+	return &spirvSource{SPIRV: compiled, traced: w.create(MethodCompileSource, cache, source)}
+}
+
+func (w *wrapper) Compute(fn func(rd.Compute)) {
+	list := w.create(MethodCompute)
+	w.Interface.Compute(func(c rd.Compute) {
+		fn(&compute{Compute: c, traced: list})
+	})
+	list.record(MethodComputeEnd)
+}
+
+func (w *wrapper) DeviceName() string {
+	name := w.Interface.DeviceName()
+	w.query(MethodDeviceName, name)
+	return name
+}
+
+func (w *wrapper) DeviceVendor() string {
+	vendor := w.Interface.DeviceVendor()
+	w.query(MethodDeviceVendor, vendor)
+	return vendor
+}
+
+func (w *wrapper) Drawing(f rd.Frame, fn func(rd.Drawing)) {
+	captured := captureFrame(f)
+	list := w.create(MethodDrawing, captured)
+	w.Interface.Drawing(captured.unwrap().frame(), func(d rd.Drawing) {
+		fn(&drawing{Drawing: d, traced: list})
+	})
+	list.record(MethodDrawingEnd)
+}
+
+func (w *wrapper) DrawingOnScreen(s rd.Screen, clear uc.Color, fn func(rd.Drawing)) {
+	list := w.create(MethodDrawingOnScreen, s, clear)
+	w.Interface.DrawingOnScreen(unwrap.Value(s), clear, func(d rd.Drawing) {
+		fn(&drawing{Drawing: d, traced: list})
+	})
+	list.record(MethodDrawingEnd)
+}
+
+func (w *wrapper) ExtensionTexture(ttype rd.TextureType, format rd.DataFormat, samples rd.TextureSamples, usage rd.TextureUsage, image uintptr, width, height, depth, layers int) rd.Texture {
+	t := w.Interface.ExtensionTexture(ttype, format, samples, usage, image, width, height, depth, layers)
+	return &texture{Texture: t, traced: w.create(MethodExtensionTexture, ttype, format, samples, usage, image, width, height, depth, layers)}
+}
+
+func (w *wrapper) FrameDelay() int {
+	delay := w.Interface.FrameDelay()
+	w.query(MethodFrameDelay, delay)
+	return delay
+}
+
+func (w *wrapper) FramebufferFormat(eyes int, attachments []rd.AttachmentFormat, passes []rd.FramebufferPass) rd.FramebufferFormat {
+	format := w.Interface.FramebufferFormat(eyes, attachments, passes)
+	return &framebufferFormat{FramebufferFormat: format, traced: w.create(MethodFramebufferFormat, eyes, attachments, passes)}
+}
+
+func (w *wrapper) IndexBufferU16(data []uint16) rd.IndexBuffer {
+	b := w.Interface.IndexBufferU16(data)
+	return &buffer{Buffer: b, traced: w.create(MethodIndexBufferU16, indices(data))}
+}
+
+func (w *wrapper) IndexBufferU32(data []uint32) rd.IndexBuffer {
+	b := w.Interface.IndexBufferU32(data)
+	return &buffer{Buffer: b, traced: w.create(MethodIndexBufferU32, indices(data))}
+}
+
+func (w *wrapper) Limit(limit rd.Limit) int {
+	value := w.Interface.Limit(limit)
+	w.query(MethodLimit, value, limit)
+	return value
+}
+
+func (w *wrapper) MemoryUsage(mtype rd.MemoryType) int {
+	usage := w.Interface.MemoryUsage(mtype)
+	w.query(MethodMemoryUsage, usage, mtype)
+	return usage
+}
+
+func (w *wrapper) PipelineCache() string {
+	uuid := w.Interface.PipelineCache()
+	w.query(MethodPipelineCache, uuid)
+	return uuid
+}
+
+func (w *wrapper) Processor(s rd.Shader, defines []any) rd.Processor {
+	p := w.Interface.Processor(unwrap.Value(s), defines)
+	return &opaque{value: p, traced: w.create(MethodProcessor, s, defines)}
+}
+
+func (w *wrapper) Renderer(s rd.Shader, options rd.RenderingOptions) rd.Renderer {
+	unwrapped := options
+	unwrapped.FramebufferFormat = unwrap.Value(options.FramebufferFormat)
+	r := w.Interface.Renderer(unwrap.Value(s), unwrapped)
+	return &renderer{Renderer: r, traced: w.create(MethodRenderer, s, options)}
+}
+
+func (w *wrapper) RenderingDevice() rd.Local {
+	device := w.Interface.RenderingDevice()
+	return &local{wrapper: wrapper{Interface: device, traced: w.create(MethodRenderingDevice)}, local: device}
+}
+
+func (w *wrapper) Sampler(state rd.SamplerState) rd.Sampler {
+	s := w.Interface.Sampler(state)
+	return &sampler{Sampler: s, traced: w.create(MethodSampler, state)}
+}
+
+func (w *wrapper) Screen(n int) rd.Screen {
+	s := w.Interface.Screen(n)
+	return &screen{Screen: s, traced: w.create(MethodScreen, n)}
+}
+
+func (w *wrapper) Shader() rd.Shader {
+	s := w.Interface.Shader()
+	return &shader{Shader: s, traced: w.create(MethodShader)}
+}
+
+func (w *wrapper) SharedTexture(view rd.TextureView, with rd.Texture) rd.Texture {
+	t := w.Interface.SharedTexture(view, unwrap.Value(with))
+	return &texture{Texture: t, traced: w.create(MethodSharedTexture, view, with)}
+}
+
+func (w *wrapper) StorageBuffer(usage rd.StorageBufferUsage, data []byte) rd.StorageBuffer {
+	b := w.Interface.StorageBuffer(usage, data)
+	return &unwrap.VariableBuffer{Variable: b, Buffer: &buffer{Buffer: b, traced: w.create(MethodStorageBuffer, usage, data)}}
+}
+
+func (w *wrapper) Texture(format rd.TextureFormat, view rd.TextureView, data [][]byte) rd.Texture {
+	t := w.Interface.Texture(format, view, data)
+	return &texture{Texture: t, traced: w.create(MethodTexture, format, view, data)}
+}
+
+func (w *wrapper) TextureBuffer(format rd.DataFormat, data []byte) rd.TextureBuffer {
+	b := w.Interface.TextureBuffer(format, data)
+	return &textureBuffer{TextureBuffer: b, traced: w.create(MethodTextureBuffer, format, data)}
+}
+
+func (w *wrapper) TextureCopy(src, dst rd.Texture, from, into, size xy.Vector3, src_mipmap, dst_mipmap, src_layer, dst_layer int, barrier rd.Barrier) error {
+	w.record(MethodTextureCopy, src, dst, from, into, size, src_mipmap, dst_mipmap, src_layer, dst_layer, barrier)
+	return w.Interface.TextureCopy(unwrap.Value(src), unwrap.Value(dst), from, into, size, src_mipmap, dst_mipmap, src_layer, dst_layer, barrier)
+}
+
+func (w *wrapper) TextureFormatIsSupportedForUsage(format rd.DataFormat, usage rd.TextureUsage) bool {
+	supported := w.Interface.TextureFormatIsSupportedForUsage(format, usage)
+	w.query(MethodTextureFormatIsSupportedForUsage, supported, format, usage)
+	return supported
+}
+
+func (w *wrapper) TextureResolveMultiSample(from, into rd.Texture, barrier rd.Barrier) error {
+	w.record(MethodTextureResolveMultiSample, from, into, barrier)
+	return w.Interface.TextureResolveMultiSample(unwrap.Value(from), unwrap.Value(into), barrier)
+}
+
+func (w *wrapper) UniformBuffer(data []byte) rd.UniformBuffer {
+	b := w.Interface.UniformBuffer(data)
+	return &unwrap.VariableBuffer{Variable: b, Buffer: &buffer{Buffer: b, traced: w.create(MethodUniformBuffer, data)}}
+}
+
+func (w *wrapper) VertexArray(vertices int, format rd.VertexFormat, buffers []rd.Buffer, offsets []int64) rd.VertexArray {
+	array := w.Interface.VertexArray(vertices, format, unwrap.Slice(buffers), offsets)
+	return &opaque{value: array, traced: w.create(MethodVertexArray, vertices, format, buffers, offsets)}
+}
+
+func (w *wrapper) VertexBuffer(data []byte) rd.VertexBuffer {
+	b := w.Interface.VertexBuffer(data)
+	return &buffer{Buffer: b, traced: w.create(MethodVertexBuffer, data)}
+}
+
+// VertexFormat is replayed, with its result used to map the vertex formats of the trace to those of the device
+// that it is replayed on.
+func (w *wrapper) VertexFormat(attributes []rd.VertexAttribute) rd.VertexFormat {
+	format := w.Interface.VertexFormat(attributes)
+	w.query(MethodVertexFormat, format, attributes)
+	return format
+}
+
+type local struct {
+	wrapper
+	local rd.Local
+}
+
+func (l *local) Submit() {
+	l.record(MethodLocalSubmit)
+	l.local.Submit()
+}
+
+func (l *local) Sync() {
+	l.record(MethodLocalSync)
+	l.local.Sync()
+}
+
+type drawing struct {
+	rd.Drawing
+	traced
+}
+
+func (d *drawing) DebugBlock(name string, color uc.Color, block func()) {
+	d.record(MethodDrawingDebugBlock, name, color)
+	d.Drawing.DebugBlock(name, color, block)
+	d.record(MethodDrawingDebugBlockEnd)
+}
+
+func (d *drawing) DebugLabel(name string, color uc.Color) {
+	d.record(MethodDrawingDebugLabel, name, color)
+	d.Drawing.DebugLabel(name, color)
+}
+
+func (d *drawing) SetBlendConstant(color uc.Color) {
+	d.record(MethodDrawingSetBlendConstant, color)
+	d.Drawing.SetBlendConstant(color)
+}
+
+func (d *drawing) SetData(data []byte) {
+	d.record(MethodDrawingSetData, data)
+	d.Drawing.SetData(data)
+}
+
+func (d *drawing) SetIndexArray(array rd.IndexArray) {
+	d.record(MethodDrawingSetIndexArray, array)
+	d.Drawing.SetIndexArray(unwrap.Value(array))
+}
+
+func (d *drawing) SetRenderer(r rd.Renderer) {
+	d.record(MethodDrawingSetRenderer, r)
+	d.Drawing.SetRenderer(unwrap.Value(r))
+}
+
+func (d *drawing) SetScissor(region *xy.Rect2) {
+	d.record(MethodDrawingSetScissor, region)
+	d.Drawing.SetScissor(region)
+}
+
+func (d *drawing) SetVariables(level rd.VariableLevel, variables rd.Variables) {
+	d.record(MethodDrawingSetVariables, level, variables)
+	d.Drawing.SetVariables(level, unwrap.Value(variables))
+}
+
+func (d *drawing) SetVertexArray(array rd.VertexArray) {
+	d.record(MethodDrawingSetVertexArray, array)
+	d.Drawing.SetVertexArray(unwrap.Value(array))
+}
+
+func (d *drawing) Submit(indices bool, instances, vertices int) {
+	d.record(MethodDrawingSubmit, indices, instances, vertices)
+	d.Drawing.Submit(indices, instances, vertices)
+}
+
+func (d *drawing) SwitchToNextPass() {
+	d.record(MethodDrawingSwitchToNextPass)
+	d.Drawing.SwitchToNextPass()
+}
+
+type compute struct {
+	rd.Compute
+	traced
+}
+
+func (c *compute) SetData(data []byte) {
+	c.record(MethodComputeSetData, data)
+	c.Compute.SetData(data)
+}
+
+func (c *compute) SetProcessor(p rd.Processor) {
+	c.record(MethodComputeSetProcessor, p)
+	c.Compute.SetProcessor(unwrap.Value(p))
+}
+
+func (c *compute) SetVariables(level rd.VariableLevel, variables rd.Variables) {
+	c.record(MethodComputeSetVariables, level, variables)
+	c.Compute.SetVariables(level, unwrap.Value(variables))
+}
+
+func (c *compute) Submit(x, y, z int) {
+	c.record(MethodComputeSubmit, x, y, z)
+	c.Compute.Submit(x, y, z)
+}
+
+type texture struct {
+	rd.Texture
+	traced
+}
+
+func (t *texture) Unwrap() any { return t.Texture }
+
+func (t *texture) Clear(color uc.Color, base_mipmap, mipmap_count, base_layer, layer_count int, barrier rd.Barrier) error {
+	t.record(MethodTextureClear, color, base_mipmap, mipmap_count, base_layer, layer_count, barrier)
+	return t.Texture.Clear(color, base_mipmap, mipmap_count, base_layer, layer_count, barrier)
+}
+
+func (t *texture) Free() {
+	t.record(MethodFree)
+	t.Texture.Free()
+}
+
+func (t *texture) SetResourceName(name string) {
+	t.record(MethodSetResourceName, name)
+	t.Texture.SetResourceName(name)
+}
+
+func (t *texture) Layer(layer int) rd.TextureData {
+	return &textureData{TextureData: t.Texture.Layer(layer), texture: t, layer: layer}
+}
+
+type textureData struct {
+	rd.TextureData
+	texture *texture
+	layer   int
+}
+
+// ReadFrom captures the data written to the layer.
+func (d *textureData) ReadFrom(r io.Reader) (int64, error) {
+	data, err := io.ReadAll(r)
+	d.texture.record(MethodTextureWrite, d.layer, data)
+	n, werr := d.TextureData.ReadFrom(bytes.NewReader(data))
+	if err == nil {
+		err = werr
+	}
+	return n, err
+}
+
+type buffer struct {
+	rd.Buffer
+	traced
+}
+
+func (b *buffer) Unwrap() any { return b.Buffer }
+
+func (b *buffer) Clear() error {
+	b.record(MethodBufferClear)
+	return b.Buffer.Clear()
+}
+
+func (b *buffer) WriteAt(p []byte, off int64) (int, error) {
+	b.record(MethodBufferWriteAt, p, off)
+	return b.Buffer.WriteAt(p, off)
+}
+
+func (b *buffer) Free() {
+	b.record(MethodFree)
+	b.Buffer.Free()
+}
+
+func (b *buffer) SetResourceName(name string) {
+	b.record(MethodSetResourceName, name)
+	b.Buffer.SetResourceName(name)
+}
+
+type textureBuffer struct {
+	rd.TextureBuffer
+	traced
+}
+
+func (b *textureBuffer) Unwrap() any { return b.TextureBuffer }
+
+func (b *textureBuffer) Free() {
+	b.record(MethodFree)
+	b.TextureBuffer.Free()
+}
+
+func (b *textureBuffer) SetResourceName(name string) {
+	b.record(MethodSetResourceName, name)
+	b.TextureBuffer.SetResourceName(name)
+}
+
+type sampler struct {
+	rd.Sampler
+	traced
+}
+
+func (s *sampler) Unwrap() any { return s.Sampler }
+
+func (s *sampler) Free() {
+	s.record(MethodFree)
+	s.Sampler.Free()
+}
+
+func (s *sampler) SetResourceName(name string) {
+	s.record(MethodSetResourceName, name)
+	s.Sampler.SetResourceName(name)
+}
+
+type shader struct {
+	rd.Shader
+	traced
+}
+
+func (s *shader) Unwrap() any { return s.Shader }
+
+func (s *shader) Compile(data []byte) {
+	s.record(MethodShaderCompile, s.recorder.binary(data))
+	s.Shader.Compile(data)
+}
+
+func (s *shader) Variables(variables map[int]rd.Variable) rd.Variables {
+	v := s.Shader.Variables(unwrap.Variables(variables))
+	return &shaderVariables{Variables: v, traced: s.create(MethodShaderVariables, variables)}
+}
+
+func (s *shader) Free() {
+	s.record(MethodFree)
+	s.Shader.Free()
+}
+
+func (s *shader) SetResourceName(name string) {
+	s.record(MethodSetResourceName, name)
+	s.Shader.SetResourceName(name)
+}
+
+type shaderVariables struct {
+	rd.Variables
+	traced
+}
+
+func (v *shaderVariables) Unwrap() any { return v.Variables }
+
+func (v *shaderVariables) Free() {
+	v.record(MethodFree)
+	v.Variables.Free()
+}
+
+type renderer struct {
+	rd.Renderer
+	traced
+}
+
+func (r *renderer) Unwrap() any { return r.Renderer }
+
+func (r *renderer) Free() {
+	r.record(MethodFree)
+	r.Renderer.Free()
+}
+
+// opaque object, such as an [rd.Processor] or [rd.VertexArray].
+type opaque struct {
+	value any
+	traced
+}
+
+func (o *opaque) Unwrap() any { return o.value }
+
+type framebufferFormat struct {
+	rd.FramebufferFormat
+	traced
+}
+
+func (f *framebufferFormat) Unwrap() any { return f.FramebufferFormat }
+
+func (f *framebufferFormat) Framebuffer(textures []rd.Texture) rd.Framebuffer {
+	fb := f.FramebufferFormat.Framebuffer(unwrap.Slice(textures))
+	return &framebuffer{Framebuffer: fb, traced: f.create(MethodFramebufferFormatFramebuffer, textures)}
+}
+
+func (f *framebufferFormat) TextureSamples(pass int) rd.TextureSamples {
+	samples := f.FramebufferFormat.TextureSamples(pass)
+	f.query(MethodFramebufferFormatTextureSamples, samples, pass)
+	return samples
+}
+
+type framebuffer struct {
+	rd.Framebuffer
+	traced
+}
+
+func (f *framebuffer) Unwrap() any { return f.Framebuffer }
+
+type screen struct {
+	rd.Screen
+	traced
+}
+
+func (s *screen) Unwrap() any { return s.Screen }
+
+func (s *screen) FramebufferFormat() rd.FramebufferFormat {
+	format := s.Screen.FramebufferFormat()
+	return &framebufferFormat{FramebufferFormat: format, traced: s.create(MethodScreenFramebufferFormat)}
+}
+
+func (s *screen) Height() int {
+	height := s.Screen.Height()
+	s.query(MethodScreenHeight, height)
+	return height
+}
+
+func (s *screen) Width() int {
+	width := s.Screen.Width()
+	s.query(MethodScreenWidth, width)
+	return width
+}
+
+type spirvSource struct {
+	rd.SPIRV
+	traced
+}
+
+func (s *spirvSource) Unwrap() any { return s.SPIRV }
+
+func (s *spirvSource) Shader(name string) rd.Shader {
+	sh := s.SPIRV.Shader(name)
+	return &shader{Shader: sh, traced: s.create(MethodSPIRVShader, name)}
+}
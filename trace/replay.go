@@ -0,0 +1,622 @@
+package trace
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"reflect"
+
+	"grow.graphics/rd"
+	"grow.graphics/uc"
+	"grow.graphics/xy"
+)
+
+/*
+Replay the trace read from r onto the device, stopping at the first call that cannot be replayed. Queries are
+not replayed and textures created by [rd.Interface.ExtensionTexture] are replaced with textures of the same
+format, as the foreign images that they were created from are not part of the trace.
+*/
+func Replay(device rd.Interface, r io.Reader) error {
+	reader, err := NewReader(r)
+	if err != nil {
+		return err
+	}
+	replay := &replayer{
+		device:  device,
+		objects: make(map[uint64]any),
+		formats: make(map[rd.VertexFormat]rd.VertexFormat),
+		lists:   make(map[uint64]*list),
+	}
+	for i := 0; ; i++ {
+		call, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := replay.call(call); err != nil {
+			return fmt.Errorf("trace: call %d (%v): %w", i, call.Method, err)
+		}
+	}
+}
+
+type replayer struct {
+	device  rd.Interface
+	objects map[uint64]any
+	formats map[rd.VertexFormat]rd.VertexFormat // vertex formats of the trace, to those of the device.
+	lists   map[uint64]*list                    // drawing and compute lists that are being recorded.
+}
+
+// list of drawing or compute commands, which are replayed once the list ends.
+type list struct {
+	begin Call
+	calls []Call
+}
+
+func (r *replayer) resolve(ref Ref) (any, error) {
+	obj, ok := r.objects[uint64(ref)]
+	if !ok {
+		return nil, fmt.Errorf("unknown object %v", ref)
+	}
+	return obj, nil
+}
+
+// args assigns the arguments of the call to the given pointers.
+func (r *replayer) args(c Call, dsts ...any) error {
+	if len(c.Args) != len(dsts) {
+		return fmt.Errorf("expected %d arguments, got %d", len(dsts), len(c.Args))
+	}
+	for i, dst := range dsts {
+		if err := assign(reflect.ValueOf(dst).Elem(), c.Args[i], r.resolve); err != nil {
+			return fmt.Errorf("argument %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// lookup the object that a method is called on.
+func lookup[T any](r *replayer, id uint64) (T, error) {
+	obj, ok := r.objects[id]
+	if !ok {
+		var zero T
+		return zero, fmt.Errorf("unknown object %v", Ref(id))
+	}
+	typed, ok := obj.(T)
+	if !ok {
+		return typed, fmt.Errorf("%v is a %T, not a %v", Ref(id), obj, reflect.TypeOf([0]T{}).Elem())
+	}
+	return typed, nil
+}
+
+func (r *replayer) vertexFormat(format rd.VertexFormat) rd.VertexFormat {
+	if mapped, ok := r.formats[format]; ok {
+		return mapped
+	}
+	return format
+}
+
+func (r *replayer) call(c Call) error {
+	if pending, ok := r.lists[c.Object]; ok && c.Object != 0 {
+		if c.Method == MethodDrawingEnd || c.Method == MethodComputeEnd {
+			delete(r.lists, c.Object)
+			return r.end(pending)
+		}
+		pending.calls = append(pending.calls, c)
+		return nil
+	}
+	if c.Method.IsQuery() || c.Method == MethodFrame {
+		return nil
+	}
+	switch c.Method {
+	case MethodCompute, MethodDrawing, MethodDrawingOnScreen:
+		r.lists[c.Result] = &list{begin: c}
+		return nil
+	case MethodLocalSubmit, MethodLocalSync:
+		device, err := lookup[rd.Local](r, c.Object)
+		if err != nil {
+			return err
+		}
+		if c.Method == MethodLocalSubmit {
+			device.Submit()
+		} else {
+			device.Sync()
+		}
+		return nil
+	case MethodFree:
+		resource, err := lookup[rd.Resource](r, c.Object)
+		if err != nil {
+			return err
+		}
+		resource.Free()
+		delete(r.objects, c.Object)
+		return nil
+	case MethodSetResourceName:
+		var name string
+		if err := r.args(c, &name); err != nil {
+			return err
+		}
+		resource, err := lookup[rd.Nameable](r, c.Object)
+		if err != nil {
+			return err
+		}
+		resource.SetResourceName(name)
+		return nil
+	case MethodTextureClear, MethodTextureWrite:
+		return r.texture(c)
+	case MethodBufferClear, MethodBufferWriteAt:
+		return r.buffer(c)
+	case MethodShaderCompile, MethodShaderVariables:
+		return r.shader(c)
+	case MethodSPIRVShader:
+		var name string
+		if err := r.args(c, &name); err != nil {
+			return err
+		}
+		source, err := lookup[rd.SPIRV](r, c.Object)
+		if err != nil {
+			return err
+		}
+		r.objects[c.Result] = source.Shader(name)
+		return nil
+	case MethodFramebufferFormatFramebuffer:
+		var textures []rd.Texture
+		if err := r.args(c, &textures); err != nil {
+			return err
+		}
+		format, err := lookup[rd.FramebufferFormat](r, c.Object)
+		if err != nil {
+			return err
+		}
+		r.objects[c.Result] = format.Framebuffer(textures)
+		return nil
+	case MethodScreenFramebufferFormat:
+		s, err := lookup[rd.Screen](r, c.Object)
+		if err != nil {
+			return err
+		}
+		r.objects[c.Result] = s.FramebufferFormat()
+		return nil
+	}
+	device := r.device
+	if c.Object != 0 {
+		var err error
+		if device, err = lookup[rd.Interface](r, c.Object); err != nil {
+			return err
+		}
+	}
+	return r.interfaceCall(device, c)
+}
+
+// interfaceCall replays a call to a method of [rd.Interface].
+func (r *replayer) interfaceCall(device rd.Interface, c Call) error {
+	var created any
+	switch c.Method {
+	case MethodBarrier:
+		var from, upto rd.Barrier
+		if err := r.args(c, &from, &upto); err != nil {
+			return err
+		}
+		device.Barrier(from, upto)
+	case MethodBarrierFull:
+		device.BarrierFull()
+	case MethodCaptureTimestamp:
+		var name string
+		if err := r.args(c, &name); err != nil {
+			return err
+		}
+		device.CaptureTimestamp(name)
+	case MethodCompileBinary:
+		var data []byte
+		if err := r.args(c, &data); err != nil {
+			return err
+		}
+		created = device.CompileBinary(data)
+	case MethodCompileSPIRV:
+		var name string
+		var source stages
+		if err := r.args(c, &name, &source); err != nil {
+			return err
+		}
+		created = device.CompileSPIRV(name, replayedSource{stages: source, device: device})
+	case MethodCompileSource:
+		var cache bool
+		var source rd.ShaderSource
+		if err := r.args(c, &cache, &source); err != nil {
+			return err
+		}
+		created = device.CompileSource(cache, source)
+	case MethodExtensionTexture:
+		var format rd.TextureFormat
+		var image uintptr
+		var layers int
+		if err := r.args(c, &format.TextureType, &format.Format, &format.Samples, &format.Usage, &image,
+			&format.Width, &format.Height, &format.Depth, &layers); err != nil {
+			return err
+		}
+		format.ArrayLayers, format.Mipmaps = layers, 1
+		created = device.Texture(format, rd.TextureView{}, nil)
+	case MethodFramebufferFormat:
+		var eyes int
+		var attachments []rd.AttachmentFormat
+		var passes []rd.FramebufferPass
+		if err := r.args(c, &eyes, &attachments, &passes); err != nil {
+			return err
+		}
+		created = device.FramebufferFormat(eyes, attachments, passes)
+	case MethodIndexBufferU16:
+		var data []byte
+		if err := r.args(c, &data); err != nil {
+			return err
+		}
+		indices := make([]uint16, len(data)/2)
+		binary.Read(bytes.NewReader(data), binary.LittleEndian, indices)
+		created = device.IndexBufferU16(indices)
+	case MethodIndexBufferU32:
+		var data []byte
+		if err := r.args(c, &data); err != nil {
+			return err
+		}
+		indices := make([]uint32, len(data)/4)
+		binary.Read(bytes.NewReader(data), binary.LittleEndian, indices)
+		created = device.IndexBufferU32(indices)
+	case MethodProcessor:
+		var shader rd.Shader
+		var defines []any
+		if err := r.args(c, &shader, &defines); err != nil {
+			return err
+		}
+		created = device.Processor(shader, defines)
+	case MethodRenderer:
+		var shader rd.Shader
+		var options rd.RenderingOptions
+		if err := r.args(c, &shader, &options); err != nil {
+			return err
+		}
+		options.VertexFormat = r.vertexFormat(options.VertexFormat)
+		created = device.Renderer(shader, options)
+	case MethodRenderingDevice:
+		created = device.RenderingDevice()
+	case MethodSampler:
+		var state rd.SamplerState
+		if err := r.args(c, &state); err != nil {
+			return err
+		}
+		created = device.Sampler(state)
+	case MethodScreen:
+		var n int
+		if err := r.args(c, &n); err != nil {
+			return err
+		}
+		created = device.Screen(n)
+	case MethodShader:
+		created = device.Shader()
+	case MethodSharedTexture:
+		var view rd.TextureView
+		var with rd.Texture
+		if err := r.args(c, &view, &with); err != nil {
+			return err
+		}
+		created = device.SharedTexture(view, with)
+	case MethodStorageBuffer:
+		var usage rd.StorageBufferUsage
+		var data []byte
+		if err := r.args(c, &usage, &data); err != nil {
+			return err
+		}
+		created = device.StorageBuffer(usage, data)
+	case MethodTexture:
+		var format rd.TextureFormat
+		var view rd.TextureView
+		var data [][]byte
+		if err := r.args(c, &format, &view, &data); err != nil {
+			return err
+		}
+		created = device.Texture(format, view, data)
+	case MethodTextureBuffer:
+		var format rd.DataFormat
+		var data []byte
+		if err := r.args(c, &format, &data); err != nil {
+			return err
+		}
+		created = device.TextureBuffer(format, data)
+	case MethodTextureCopy:
+		var src, dst rd.Texture
+		var from, into, size xy.Vector3
+		var srcMipmap, dstMipmap, srcLayer, dstLayer int
+		var barrier rd.Barrier
+		if err := r.args(c, &src, &dst, &from, &into, &size, &srcMipmap, &dstMipmap, &srcLayer, &dstLayer, &barrier); err != nil {
+			return err
+		}
+		return device.TextureCopy(src, dst, from, into, size, srcMipmap, dstMipmap, srcLayer, dstLayer, barrier)
+	case MethodTextureResolveMultiSample:
+		var from, into rd.Texture
+		var barrier rd.Barrier
+		if err := r.args(c, &from, &into, &barrier); err != nil {
+			return err
+		}
+		return device.TextureResolveMultiSample(from, into, barrier)
+	case MethodUniformBuffer:
+		var data []byte
+		if err := r.args(c, &data); err != nil {
+			return err
+		}
+		created = device.UniformBuffer(data)
+	case MethodVertexArray:
+		var vertices int
+		var format rd.VertexFormat
+		var buffers []rd.Buffer
+		var offsets []int64
+		if err := r.args(c, &vertices, &format, &buffers, &offsets); err != nil {
+			return err
+		}
+		created = device.VertexArray(vertices, r.vertexFormat(format), buffers, offsets)
+	case MethodVertexBuffer:
+		var data []byte
+		if err := r.args(c, &data); err != nil {
+			return err
+		}
+		created = device.VertexBuffer(data)
+	case MethodVertexFormat:
+		var attributes []rd.VertexAttribute
+		if err := r.args(c, &attributes); err != nil {
+			return err
+		}
+		var recorded rd.VertexFormat
+		if err := assign(reflect.ValueOf(&recorded).Elem(), c.Return, r.resolve); err != nil {
+			return fmt.Errorf("result: %w", err)
+		}
+		r.formats[recorded] = device.VertexFormat(attributes)
+	default:
+		return fmt.Errorf("cannot replay %v on a device", c.Method)
+	}
+	if c.Result != 0 {
+		r.objects[c.Result] = created
+	}
+	return nil
+}
+
+func (r *replayer) texture(c Call) error {
+	t, err := lookup[rd.Texture](r, c.Object)
+	if err != nil {
+		return err
+	}
+	if c.Method == MethodTextureWrite {
+		var layer int
+		var data []byte
+		if err := r.args(c, &layer, &data); err != nil {
+			return err
+		}
+		w := t.Layer(layer)
+		defer w.Close()
+		_, err := w.ReadFrom(bytes.NewReader(data))
+		return err
+	}
+	var color uc.Color
+	var baseMipmap, mipmapCount, baseLayer, layerCount int
+	var barrier rd.Barrier
+	if err := r.args(c, &color, &baseMipmap, &mipmapCount, &baseLayer, &layerCount, &barrier); err != nil {
+		return err
+	}
+	return t.Clear(color, baseMipmap, mipmapCount, baseLayer, layerCount, barrier)
+}
+
+func (r *replayer) buffer(c Call) error {
+	b, err := lookup[rd.Buffer](r, c.Object)
+	if err != nil {
+		return err
+	}
+	if c.Method == MethodBufferClear {
+		return b.Clear()
+	}
+	var data []byte
+	var offset int64
+	if err := r.args(c, &data, &offset); err != nil {
+		return err
+	}
+	_, err = b.WriteAt(data, offset)
+	return err
+}
+
+func (r *replayer) shader(c Call) error {
+	s, err := lookup[rd.Shader](r, c.Object)
+	if err != nil {
+		return err
+	}
+	if c.Method == MethodShaderCompile {
+		var data []byte
+		if err := r.args(c, &data); err != nil {
+			return err
+		}
+		s.Compile(data)
+		return nil
+	}
+	var variables map[int]rd.Variable
+	if err := r.args(c, &variables); err != nil {
+		return err
+	}
+	r.objects[c.Result] = s.Variables(variables)
+	return nil
+}
+
+// end replays a drawing or compute list.
+func (r *replayer) end(l *list) error {
+	device := r.device
+	if l.begin.Object != 0 {
+		var err error
+		if device, err = lookup[rd.Interface](r, l.begin.Object); err != nil {
+			return err
+		}
+	}
+	var err error
+	switch l.begin.Method {
+	case MethodCompute:
+		device.Compute(func(c rd.Compute) {
+			err = r.compute(c, l.calls)
+		})
+	case MethodDrawing:
+		var f frame
+		if err := r.args(l.begin, &f); err != nil {
+			return err
+		}
+		device.Drawing(f.frame(), func(d rd.Drawing) {
+			err = r.drawing(d, l.calls)
+		})
+	case MethodDrawingOnScreen:
+		var s rd.Screen
+		var clear uc.Color
+		if err := r.args(l.begin, &s, &clear); err != nil {
+			return err
+		}
+		device.DrawingOnScreen(s, clear, func(d rd.Drawing) {
+			err = r.drawing(d, l.calls)
+		})
+	}
+	return err
+}
+
+func (r *replayer) compute(compute rd.Compute, calls []Call) error {
+	for _, c := range calls {
+		switch c.Method {
+		case MethodComputeSetData:
+			var data []byte
+			if err := r.args(c, &data); err != nil {
+				return err
+			}
+			compute.SetData(data)
+		case MethodComputeSetProcessor:
+			var p rd.Processor
+			if err := r.args(c, &p); err != nil {
+				return err
+			}
+			compute.SetProcessor(p)
+		case MethodComputeSetVariables:
+			var level rd.VariableLevel
+			var variables rd.Variables
+			if err := r.args(c, &level, &variables); err != nil {
+				return err
+			}
+			compute.SetVariables(level, variables)
+		case MethodComputeSubmit:
+			var x, y, z int
+			if err := r.args(c, &x, &y, &z); err != nil {
+				return err
+			}
+			compute.Submit(x, y, z)
+		default:
+			return fmt.Errorf("cannot replay %v within a compute list", c.Method)
+		}
+	}
+	return nil
+}
+
+func (r *replayer) drawing(drawing rd.Drawing, calls []Call) error {
+	for i := 0; i < len(calls); i++ {
+		c := calls[i]
+		switch c.Method {
+		case MethodDrawingDebugBlock:
+			var name string
+			var color uc.Color
+			if err := r.args(c, &name, &color); err != nil {
+				return err
+			}
+			end, depth := i+1, 1
+			for ; end < len(calls); end++ {
+				if calls[end].Method == MethodDrawingDebugBlock {
+					depth++
+				} else if calls[end].Method == MethodDrawingDebugBlockEnd {
+					if depth--; depth == 0 {
+						break
+					}
+				}
+			}
+			var err error
+			drawing.DebugBlock(name, color, func() {
+				err = r.drawing(drawing, calls[i+1:end])
+			})
+			if err != nil {
+				return err
+			}
+			i = end
+		case MethodDrawingDebugLabel:
+			var name string
+			var color uc.Color
+			if err := r.args(c, &name, &color); err != nil {
+				return err
+			}
+			drawing.DebugLabel(name, color)
+		case MethodDrawingSetBlendConstant:
+			var color uc.Color
+			if err := r.args(c, &color); err != nil {
+				return err
+			}
+			drawing.SetBlendConstant(color)
+		case MethodDrawingSetData:
+			var data []byte
+			if err := r.args(c, &data); err != nil {
+				return err
+			}
+			drawing.SetData(data)
+		case MethodDrawingSetIndexArray:
+			var array rd.IndexArray
+			if err := r.args(c, &array); err != nil {
+				return err
+			}
+			drawing.SetIndexArray(array)
+		case MethodDrawingSetRenderer:
+			var renderer rd.Renderer
+			if err := r.args(c, &renderer); err != nil {
+				return err
+			}
+			drawing.SetRenderer(renderer)
+		case MethodDrawingSetScissor:
+			var region *xy.Rect2
+			if err := r.args(c, &region); err != nil {
+				return err
+			}
+			drawing.SetScissor(region)
+		case MethodDrawingSetVariables:
+			var level rd.VariableLevel
+			var variables rd.Variables
+			if err := r.args(c, &level, &variables); err != nil {
+				return err
+			}
+			drawing.SetVariables(level, variables)
+		case MethodDrawingSetVertexArray:
+			var array rd.VertexArray
+			if err := r.args(c, &array); err != nil {
+				return err
+			}
+			drawing.SetVertexArray(array)
+		case MethodDrawingSubmit:
+			var indices bool
+			var instances, vertices int
+			if err := r.args(c, &indices, &instances, &vertices); err != nil {
+				return err
+			}
+			drawing.Submit(indices, instances, vertices)
+		case MethodDrawingSwitchToNextPass:
+			drawing.SwitchToNextPass()
+		default:
+			return fmt.Errorf("cannot replay %v within a drawing list", c.Method)
+		}
+	}
+	return nil
+}
+
+// replayedSource is the SPIR-V captured by [rd.Interface.CompileSPIRV].
+type replayedSource struct {
+	stages stages
+	device rd.Interface
+}
+
+func (s replayedSource) Compute() []byte               { return s.stages.Compute }
+func (s replayedSource) Fragment() []byte              { return s.stages.Fragment }
+func (s replayedSource) TesselationControl() []byte    { return s.stages.TesselationControl }
+func (s replayedSource) TesselationEvaluation() []byte { return s.stages.TesselationEvaluation }
+func (s replayedSource) Vertex() []byte                { return s.stages.Vertex }
+
+func (s replayedSource) Shader(name string) rd.Shader {
+	return s.device.CompileBinary(s.device.CompileSPIRV(name, s))
+}
@@ -0,0 +1,335 @@
+/*
+Package trace captures the calls made to an [rd.Interface] (along with the calls made to the resources that it
+creates) into a compact, versioned, binary trace file that can be replayed onto any other rendering device,
+such that a rendering bug can be reported as a reproducible trace, rather than a description.
+
+	device, recorder := trace.Wrap(RD, file)
+	defer recorder.Flush()
+	...
+	recorder.Frame() // at the end of each frame.
+
+A trace can then be replayed onto another device, with [Replay], or inspected call by call with a [Reader].
+
+	err := trace.Replay(RD, file)
+
+Resources are created with their initial data, and data written to them (such as with [rd.Buffer.WriteAt]) is
+captured as part of the trace. Shader binaries are specific to the device that compiled them, so traces refer
+to the [rd.Interface.CompileSPIRV] call that produced a binary, so that it is recompiled for the device that
+the trace is replayed on. Queries (such as [rd.Interface.Limit]) are captured with their results, but are not
+replayed, calls that only read from resources are not captured.
+
+# Format
+
+A trace starts with the four bytes "RDTR" and the version of the format as a uvarint, followed by a sequence of
+calls. Each call is made up of its [Method], the ID of the object that the method was called on (zero for the
+rendering device that was wrapped), the ID assigned to the object that the call created (or zero) as uvarints,
+followed by its arguments as a list value and its result as a value.
+
+Each value starts with a tag byte, followed by its encoding:
+
+	0 nil
+	1 bool, one byte.
+	2 signed integer, as a varint.
+	3 unsigned integer, as a uvarint.
+	4 float, the IEEE 754 bits of a float64 as a little-endian uint64.
+	5 string, uvarint length followed by the bytes.
+	6 bytes, uvarint length followed by the bytes.
+	7 list (or struct, by exported fields in order), uvarint length followed by the values.
+	8 map, uvarint length followed by each key and value, ordered by key.
+	9 reference to an object, by its ID as a uvarint.
+	10 typed value held by an interface, the name of its type as a string value, followed by the value.
+*/
+package trace
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+)
+
+// Version of the trace format written by this package.
+const Version = 1
+
+// magic bytes at the start of each trace.
+const magic = "RDTR"
+
+// Method identifies the method of a [Call].
+type Method uint16
+
+const (
+	MethodFrame Method = iota // end of a frame, see [Recorder.Frame].
+
+	MethodBarrier
+	MethodBarrierFull
+	MethodCaptureTimestamp
+	MethodCompileBinary
+	MethodCompileSPIRV
+	MethodCompileSource
+	MethodCompute
+	MethodComputeEnd
+	MethodDeviceName
+	MethodDeviceVendor
+	MethodDrawing
+	MethodDrawingEnd
+	MethodDrawingOnScreen
+	MethodExtensionTexture
+	MethodFrameDelay
+	MethodFramebufferFormat
+	MethodIndexBufferU16
+	MethodIndexBufferU32
+	MethodLimit
+	MethodMemoryUsage
+	MethodPipelineCache
+	MethodProcessor
+	MethodRenderer
+	MethodRenderingDevice
+	MethodSampler
+	MethodScreen
+	MethodShader
+	MethodSharedTexture
+	MethodStorageBuffer
+	MethodTexture
+	MethodTextureBuffer
+	MethodTextureCopy
+	MethodTextureFormatIsSupportedForUsage
+	MethodTextureResolveMultiSample
+	MethodUniformBuffer
+	MethodVertexArray
+	MethodVertexBuffer
+	MethodVertexFormat
+
+	MethodLocalSubmit
+	MethodLocalSync
+
+	MethodDrawingDebugBlock
+	MethodDrawingDebugBlockEnd
+	MethodDrawingDebugLabel
+	MethodDrawingSetBlendConstant
+	MethodDrawingSetData
+	MethodDrawingSetIndexArray
+	MethodDrawingSetRenderer
+	MethodDrawingSetScissor
+	MethodDrawingSetVariables
+	MethodDrawingSetVertexArray
+	MethodDrawingSubmit
+	MethodDrawingSwitchToNextPass
+
+	MethodComputeSetData
+	MethodComputeSetProcessor
+	MethodComputeSetVariables
+	MethodComputeSubmit
+
+	MethodFree
+	MethodSetResourceName
+
+	MethodTextureClear
+	MethodTextureWrite // write to a layer of a texture, see [rd.Texture.Layer].
+	MethodBufferClear
+	MethodBufferWriteAt
+	MethodShaderCompile
+	MethodShaderVariables
+	MethodSPIRVShader
+	MethodFramebufferFormatFramebuffer
+	MethodFramebufferFormatTextureSamples
+	MethodScreenFramebufferFormat
+	MethodScreenHeight
+	MethodScreenWidth
+
+	methodCount
+)
+
+var methodNames = [methodCount]string{
+	MethodFrame:                            "Frame",
+	MethodBarrier:                          "Barrier",
+	MethodBarrierFull:                      "BarrierFull",
+	MethodCaptureTimestamp:                 "CaptureTimestamp",
+	MethodCompileBinary:                    "CompileBinary",
+	MethodCompileSPIRV:                     "CompileSPIRV",
+	MethodCompileSource:                    "CompileSource",
+	MethodCompute:                          "Compute",
+	MethodComputeEnd:                       "Compute.End",
+	MethodDeviceName:                       "DeviceName",
+	MethodDeviceVendor:                     "DeviceVendor",
+	MethodDrawing:                          "Drawing",
+	MethodDrawingEnd:                       "Drawing.End",
+	MethodDrawingOnScreen:                  "DrawingOnScreen",
+	MethodExtensionTexture:                 "ExtensionTexture",
+	MethodFrameDelay:                       "FrameDelay",
+	MethodFramebufferFormat:                "FramebufferFormat",
+	MethodIndexBufferU16:                   "IndexBufferU16",
+	MethodIndexBufferU32:                   "IndexBufferU32",
+	MethodLimit:                            "Limit",
+	MethodMemoryUsage:                      "MemoryUsage",
+	MethodPipelineCache:                    "PipelineCache",
+	MethodProcessor:                        "Processor",
+	MethodRenderer:                         "Renderer",
+	MethodRenderingDevice:                  "RenderingDevice",
+	MethodSampler:                          "Sampler",
+	MethodScreen:                           "Screen",
+	MethodShader:                           "Shader",
+	MethodSharedTexture:                    "SharedTexture",
+	MethodStorageBuffer:                    "StorageBuffer",
+	MethodTexture:                          "Texture",
+	MethodTextureBuffer:                    "TextureBuffer",
+	MethodTextureCopy:                      "TextureCopy",
+	MethodTextureFormatIsSupportedForUsage: "TextureFormatIsSupportedForUsage",
+	MethodTextureResolveMultiSample:        "TextureResolveMultiSample",
+	MethodUniformBuffer:                    "UniformBuffer",
+	MethodVertexArray:                      "VertexArray",
+	MethodVertexBuffer:                     "VertexBuffer",
+	MethodVertexFormat:                     "VertexFormat",
+	MethodLocalSubmit:                      "Local.Submit",
+	MethodLocalSync:                        "Local.Sync",
+	MethodDrawingDebugBlock:                "Drawing.DebugBlock",
+	MethodDrawingDebugBlockEnd:             "Drawing.DebugBlock.End",
+	MethodDrawingDebugLabel:                "Drawing.DebugLabel",
+	MethodDrawingSetBlendConstant:          "Drawing.SetBlendConstant",
+	MethodDrawingSetData:                   "Drawing.SetData",
+	MethodDrawingSetIndexArray:             "Drawing.SetIndexArray",
+	MethodDrawingSetRenderer:               "Drawing.SetRenderer",
+	MethodDrawingSetScissor:                "Drawing.SetScissor",
+	MethodDrawingSetVariables:              "Drawing.SetVariables",
+	MethodDrawingSetVertexArray:            "Drawing.SetVertexArray",
+	MethodDrawingSubmit:                    "Drawing.Submit",
+	MethodDrawingSwitchToNextPass:          "Drawing.SwitchToNextPass",
+	MethodComputeSetData:                   "Compute.SetData",
+	MethodComputeSetProcessor:              "Compute.SetProcessor",
+	MethodComputeSetVariables:              "Compute.SetVariables",
+	MethodComputeSubmit:                    "Compute.Submit",
+	MethodFree:                             "Resource.Free",
+	MethodSetResourceName:                  "Nameable.SetResourceName",
+	MethodTextureClear:                     "Texture.Clear",
+	MethodTextureWrite:                     "Texture.Write",
+	MethodBufferClear:                      "Buffer.Clear",
+	MethodBufferWriteAt:                    "Buffer.WriteAt",
+	MethodShaderCompile:                    "Shader.Compile",
+	MethodShaderVariables:                  "Shader.Variables",
+	MethodSPIRVShader:                      "SPIRV.Shader",
+	MethodFramebufferFormatFramebuffer:     "FramebufferFormat.Framebuffer",
+	MethodFramebufferFormatTextureSamples:  "FramebufferFormat.TextureSamples",
+	MethodScreenFramebufferFormat:          "Screen.FramebufferFormat",
+	MethodScreenHeight:                     "Screen.Height",
+	MethodScreenWidth:                      "Screen.Width",
+}
+
+// String returns the name of the method, such as "Drawing.Submit", methods of [rd.Interface] are unqualified.
+func (m Method) String() string {
+	if m < methodCount {
+		return methodNames[m]
+	}
+	return fmt.Sprintf("Method(%d)", uint16(m))
+}
+
+// IsQuery reports whether the method only queries the device, such that it is not replayed.
+func (m Method) IsQuery() bool {
+	switch m {
+	case MethodDeviceName, MethodDeviceVendor, MethodFrameDelay, MethodLimit, MethodMemoryUsage, MethodPipelineCache,
+		MethodTextureFormatIsSupportedForUsage, MethodFramebufferFormatTextureSamples, MethodScreenHeight,
+		MethodScreenWidth:
+		return true
+	default:
+		return false
+	}
+}
+
+/*
+Call captured in a trace. Arguments and results are decoded into their generic form, which is one of:
+
+	nil, bool, int64, uint64, float64, string, []byte
+	[]any   for lists, arrays and structs (by exported fields, in order).
+	[]Entry for maps.
+	Ref     for objects created by an earlier call.
+	Typed   for values held by an interface.
+*/
+type Call struct {
+	Method Method
+	Object uint64 // ID of the object that the method was called on, zero for the wrapped device.
+	Result uint64 // ID of the object created by the call, or zero.
+	Args   []any
+	Return any // result of a query, or nil.
+}
+
+// Ref to an object created by an earlier call, by the ID of that object.
+type Ref uint64
+
+func (r Ref) String() string { return fmt.Sprintf("#%d", uint64(r)) }
+
+// Typed value held by an interface, such as one of the defines passed to [rd.Interface.Processor].
+type Typed struct {
+	Type  string // name of the type, such as "float32" or "rd.SamplerWithTexture".
+	Value any
+}
+
+func (t Typed) String() string { return t.Type + "(" + Format(t.Value) + ")" }
+
+// Entry of a map.
+type Entry struct {
+	Key, Value any
+}
+
+// String returns the call in a form that is suitable for comparing calls, such as:
+//
+//	#3 = Texture({1 1 37 4 1 0 1 3 4 map[]}, {0 0 0 0 0}, {<64 bytes 9f0a3c21>})
+func (c Call) String() string { return c.Format(Ref.String) }
+
+// Format returns the call like [Call.String], with references to objects formatted by name.
+func (c Call) Format(name func(Ref) string) string {
+	var b strings.Builder
+	if c.Result != 0 {
+		b.WriteString(name(Ref(c.Result)))
+		b.WriteString(" = ")
+	}
+	if c.Object != 0 {
+		b.WriteString(name(Ref(c.Object)))
+		b.WriteByte('.')
+	}
+	b.WriteString(c.Method.String())
+	b.WriteByte('(')
+	for i, arg := range c.Args {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(format(arg, name))
+	}
+	b.WriteByte(')')
+	if c.Return != nil {
+		b.WriteString(" -> ")
+		b.WriteString(format(c.Return, name))
+	}
+	return b.String()
+}
+
+// Format returns a compact representation of a value in its generic form, where byte slices are summarized
+// by their length and a hash of their contents.
+func Format(value any) string { return format(value, Ref.String) }
+
+func format(value any, name func(Ref) string) string {
+	switch v := value.(type) {
+	case nil:
+		return "nil"
+	case []byte:
+		hash := fnv.New32a()
+		hash.Write(v)
+		return fmt.Sprintf("<%d bytes %08x>", len(v), hash.Sum32())
+	case string:
+		return fmt.Sprintf("%q", v)
+	case Ref:
+		return name(v)
+	case Typed:
+		return v.Type + "(" + format(v.Value, name) + ")"
+	case []any:
+		parts := make([]string, len(v))
+		for i, elem := range v {
+			parts[i] = format(elem, name)
+		}
+		return "{" + strings.Join(parts, " ") + "}"
+	case []Entry:
+		parts := make([]string, len(v))
+		for i, entry := range v {
+			parts[i] = format(entry.Key, name) + ":" + format(entry.Value, name)
+		}
+		return "map[" + strings.Join(parts, " ") + "]"
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,200 @@
+package rd
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"reflect"
+)
+
+/*
+Typed view over a [Buffer], where each element is a T encoded according to a [Layout]. Views created by
+[NewTyped] and [TypedVertexBuffer] use the fixed little-endian binary layout of [encoding/binary], without
+any padding between fields, which suits vertex buffers. Uniform and storage buffers must follow the std140
+and std430 layout rules instead, so their views are created by the layout package, see
+[grow.graphics/rd/layout.UniformBuffer] and [grow.graphics/rd/layout.StorageBuffer].
+
+	vertices, view := rd.TypedVertexBuffer[Vertex](RD, 1024)
+	if err := view.Append(Vertex{...}, Vertex{...}); err != nil {
+		return err
+	}
+	first, err := view.Get(0)
+
+Like a Go slice, a typed view has a length and a capacity, where the capacity is fixed by the
+size of the underlying buffer.
+*/
+type Typed[T any] struct {
+	buffer   Buffer
+	layout   Layout[T]
+	offset   int64 // in bytes.
+	length   int
+	capacity int
+	size     int // stride of each element, in bytes.
+}
+
+// Layout of the elements of a [Typed] view within its buffer.
+type Layout[T any] interface {
+	// Stride returns the number of bytes between consecutive elements.
+	Stride() int
+	// Encode the values into buf, which is len(values)*Stride() bytes long and zeroed.
+	Encode(buf []byte, values []T) error
+	// Decode the values from buf, which is len(values)*Stride() bytes long.
+	Decode(buf []byte, values []T) error
+}
+
+// NewTyped returns a typed view over the buffer, with the given length and capacity (in elements), where
+// the elements are encoded with [encoding/binary]. Panics if T is not a fixed-size type.
+func NewTyped[T any](buffer Buffer, length, capacity int) *Typed[T] {
+	return NewTypedLayout[T](buffer, packed[T]{size: packedSize[T]()}, length, capacity)
+}
+
+// NewTypedLayout returns a typed view over the buffer, with the given layout, length and capacity (in elements).
+func NewTypedLayout[T any](buffer Buffer, layout Layout[T], length, capacity int) *Typed[T] {
+	if length < 0 || length > capacity {
+		panic(fmt.Sprintf("rd: typed buffer length %d out of range [0:%d]", length, capacity))
+	}
+	return &Typed[T]{
+		buffer:   buffer,
+		layout:   layout,
+		length:   length,
+		capacity: capacity,
+		size:     layout.Stride(),
+	}
+}
+
+// TypedData encodes the values with the layout, into a zeroed buffer large enough for capacity elements.
+func TypedData[T any](layout Layout[T], capacity int, values []T) ([]byte, error) {
+	data := make([]byte, max(capacity, len(values))*layout.Stride())
+	if err := layout.Encode(data[:len(values)*layout.Stride()], values); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func packedSize[T any]() int {
+	var zero T
+	size := binary.Size(zero)
+	if size <= 0 {
+		panic(fmt.Sprintf("rd: %v is not a fixed-size type", reflect.TypeOf([0]T{}).Elem()))
+	}
+	return size
+}
+
+// packed layout of fixed-size values, as encoded by [encoding/binary].
+type packed[T any] struct {
+	size int
+}
+
+func (p packed[T]) Stride() int { return p.size }
+
+func (p packed[T]) Encode(buf []byte, values []T) error {
+	var w bytes.Buffer
+	if err := binary.Write(&w, binary.LittleEndian, values); err != nil {
+		return err
+	}
+	copy(buf, w.Bytes())
+	return nil
+}
+
+func (p packed[T]) Decode(buf []byte, values []T) error {
+	return binary.Read(bytes.NewReader(buf), binary.LittleEndian, values)
+}
+
+// TypedVertexBuffer creates a vertex buffer with room for capacity elements, containing the given values.
+// Panics if T is not a fixed-size type.
+func TypedVertexBuffer[T any](device Interface, capacity int, values ...T) (VertexBuffer, *Typed[T]) {
+	layout := packed[T]{size: packedSize[T]()}
+	data, err := TypedData[T](layout, capacity, values)
+	if err != nil {
+		panic(err) // unreachable for fixed-size types.
+	}
+	buffer := device.VertexBuffer(data)
+	return buffer, NewTypedLayout[T](buffer, layout, len(values), max(capacity, len(values)))
+}
+
+// Buffer returns the underlying buffer.
+func (t *Typed[T]) Buffer() Buffer { return t.buffer }
+
+// Len returns the number of elements in the view.
+func (t *Typed[T]) Len() int { return t.length }
+
+// Cap returns the maximum number of elements the view can hold.
+func (t *Typed[T]) Cap() int { return t.capacity }
+
+func (t *Typed[T]) checkIndex(i int) error {
+	if i < 0 || i >= t.length {
+		return fmt.Errorf("rd: typed buffer index %d out of range [0:%d]", i, t.length)
+	}
+	return nil
+}
+
+// Get reads the element at index i from the buffer.
+func (t *Typed[T]) Get(i int) (T, error) {
+	var value T
+	if err := t.checkIndex(i); err != nil {
+		return value, err
+	}
+	buf := make([]byte, t.size)
+	if _, err := t.buffer.ReadAt(buf, t.offset+int64(i*t.size)); err != nil {
+		return value, err
+	}
+	values := []T{value}
+	err := t.layout.Decode(buf, values)
+	return values[0], err
+}
+
+// Set writes v to the element at index i of the buffer.
+func (t *Typed[T]) Set(i int, v T) error {
+	if err := t.checkIndex(i); err != nil {
+		return err
+	}
+	return t.write(i, []T{v})
+}
+
+func (t *Typed[T]) write(i int, values []T) error {
+	buf := make([]byte, len(values)*t.size)
+	if err := t.layout.Encode(buf, values); err != nil {
+		return err
+	}
+	_, err := t.buffer.WriteAt(buf, t.offset+int64(i*t.size))
+	return err
+}
+
+// Values reads all of the elements in the view.
+func (t *Typed[T]) Values() ([]T, error) {
+	buf := make([]byte, t.length*t.size)
+	if _, err := t.buffer.ReadAt(buf, t.offset); err != nil {
+		return nil, err
+	}
+	values := make([]T, t.length)
+	return values, t.layout.Decode(buf, values)
+}
+
+// Slice returns a view of the elements [lo:hi], sharing the same buffer. Like a Go slice, the capacity
+// of the result extends to the capacity of t, panics if the indices are out of range.
+func (t *Typed[T]) Slice(lo, hi int) *Typed[T] {
+	if lo < 0 || hi < lo || hi > t.capacity {
+		panic(fmt.Sprintf("rd: typed buffer slice bounds [%d:%d] out of range with capacity %d", lo, hi, t.capacity))
+	}
+	return &Typed[T]{
+		buffer:   t.buffer,
+		layout:   t.layout,
+		offset:   t.offset + int64(lo*t.size),
+		length:   hi - lo,
+		capacity: t.capacity - lo,
+		size:     t.size,
+	}
+}
+
+// Append writes the values after the last element of the view, returning an error if they
+// exceed the capacity of the view.
+func (t *Typed[T]) Append(values ...T) error {
+	if t.length+len(values) > t.capacity {
+		return fmt.Errorf("rd: appending %d elements to a typed buffer of length %d exceeds its capacity of %d", len(values), t.length, t.capacity)
+	}
+	if err := t.write(t.length, values); err != nil {
+		return err
+	}
+	t.length += len(values)
+	return nil
+}
@@ -0,0 +1,315 @@
+/*
+Package validate wraps an [rd.Interface] with additional checks that are too expensive, or require too much
+information, to be performed by the rendering device itself. Reflection of the SPIR-V that shaders are
+compiled from is used to check that the options passed to the device match what the shaders expect, and
+SPIR-V is validated (see [spirv.Bundle.Validate]) before it is passed to the device.
+
+	RD = validate.Wrap(RD, func(err error) {
+		log.Println(err)
+	})
+*/
+package validate
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sync"
+
+	"grow.graphics/rd"
+	"grow.graphics/rd/spirv"
+	"grow.graphics/uc"
+)
+
+// Wrap the device, such that each failed check is passed to report. If report is nil, then
+// failed checks will panic.
+func Wrap(device rd.Interface, report func(error)) rd.Interface {
+	if report == nil {
+		report = func(err error) { panic(err) }
+	}
+	return wrapper{
+		Interface: device,
+		state: &state{
+			report:   report,
+			binaries: make(map[[sha256.Size]byte]reflections),
+			formats:  make(map[rd.VertexFormat][]rd.VertexAttribute),
+		},
+	}
+}
+
+type reflections map[spirv.ExecutionModel]*spirv.Reflection
+
+// state shared between a device and any of its local devices.
+type state struct {
+	report func(error)
+
+	mutex    sync.Mutex
+	binaries map[[sha256.Size]byte]reflections
+	formats  map[rd.VertexFormat][]rd.VertexAttribute
+}
+
+func (s *state) reflect(source rd.SPIRV) reflections {
+	bundle, err := spirv.NewBundle(source)
+	if err != nil {
+		s.report(err)
+		return nil
+	}
+	reflected, err := bundle.Reflect()
+	if err != nil {
+		s.report(err)
+		return nil
+	}
+	return reflected
+}
+
+func (s *state) binary(data []byte) reflections {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.binaries[sha256.Sum256(data)]
+}
+
+type wrapper struct {
+	rd.Interface
+	state *state
+}
+
+type local struct {
+	wrapper
+	local rd.Local
+}
+
+func (l local) Submit() { l.local.Submit() }
+func (l local) Sync()   { l.local.Sync() }
+
+func (w wrapper) RenderingDevice() rd.Local {
+	device := w.Interface.RenderingDevice()
+	return local{wrapper: wrapper{Interface: device, state: w.state}, local: device}
+}
+
+func (w wrapper) CompileSPIRV(name string, source rd.SPIRV) []byte {
+	if wrapped, ok := source.(shaderSource); ok {
+		source = wrapped.SPIRV
+	}
+	bundle, err := spirv.NewBundle(source)
+	if err == nil {
+		err = bundle.Validate()
+	}
+	if err != nil {
+		w.state.report(fmt.Errorf("rd.Interface.CompileSPIRV: %s: %w", name, err))
+		return nil // malformed SPIR-V must not reach the driver.
+	}
+	binary := w.Interface.CompileSPIRV(name, source)
+	if reflected, err := bundle.Reflect(); err != nil {
+		w.state.report(err)
+	} else {
+		w.state.mutex.Lock()
+		w.state.binaries[sha256.Sum256(binary)] = reflected
+		w.state.mutex.Unlock()
+	}
+	return binary
+}
+
+func (w wrapper) CompileSource(cache bool, source rd.ShaderSource) rd.SPIRV {
+	return shaderSource{SPIRV: w.Interface.CompileSource(cache, source), state: w.state}
+}
+
+func (w wrapper) CompileBinary(data []byte) rd.Shader {
+	return &shader{Shader: w.Interface.CompileBinary(data), state: w.state, reflections: w.state.binary(data)}
+}
+
+func (w wrapper) Shader() rd.Shader {
+	return &shader{Shader: w.Interface.Shader(), state: w.state}
+}
+
+func (w wrapper) VertexFormat(attributes []rd.VertexAttribute) rd.VertexFormat {
+	format := w.Interface.VertexFormat(attributes)
+	w.state.mutex.Lock()
+	w.state.formats[format] = append([]rd.VertexAttribute(nil), attributes...)
+	w.state.mutex.Unlock()
+	return format
+}
+
+func (w wrapper) Processor(s rd.Shader, defines []any) rd.Processor {
+	wrapped, ok := s.(*shader)
+	if !ok {
+		return w.Interface.Processor(s, defines)
+	}
+	w.checkDefines("rd.Interface.Processor", wrapped, defines)
+	return processor{Processor: w.Interface.Processor(wrapped.Shader, defines), shader: wrapped}
+}
+
+func (w wrapper) Renderer(s rd.Shader, options rd.RenderingOptions) rd.Renderer {
+	wrapped, ok := s.(*shader)
+	if !ok {
+		return w.Interface.Renderer(s, options)
+	}
+	w.checkVertexFormat(wrapped, options.VertexFormat)
+	w.checkDefines("rd.Interface.Renderer", wrapped, options.ShaderDefines)
+	return renderer{Renderer: w.Interface.Renderer(wrapped.Shader, options), shader: wrapped}
+}
+
+func (w wrapper) Drawing(frame rd.Frame, fn func(rd.Drawing)) {
+	w.Interface.Drawing(frame, func(d rd.Drawing) {
+		fn(&drawing{Drawing: d, state: w.state})
+	})
+}
+
+func (w wrapper) DrawingOnScreen(screen rd.Screen, clear uc.Color, fn func(rd.Drawing)) {
+	w.Interface.DrawingOnScreen(screen, clear, func(d rd.Drawing) {
+		fn(&drawing{Drawing: d, state: w.state})
+	})
+}
+
+func (w wrapper) Compute(fn func(rd.Compute)) {
+	w.Interface.Compute(func(c rd.Compute) {
+		fn(&compute{Compute: c, state: w.state})
+	})
+}
+
+// checkDefines checks that the defines match the types of the specialization constants of the shader.
+func (w wrapper) checkDefines(method string, s *shader, defines []any) {
+	if s.reflections == nil {
+		return
+	}
+	stages := make([]*spirv.Reflection, 0, len(s.reflections))
+	for _, reflection := range s.reflections {
+		stages = append(stages, reflection)
+	}
+	if err := spirv.CheckDefines(defines, stages...); err != nil {
+		w.state.report(fmt.Errorf("%s: %w", method, err))
+	}
+}
+
+// checkVertexFormat checks that every input location of the vertex stage is fed by a vertex attribute.
+func (w wrapper) checkVertexFormat(s *shader, format rd.VertexFormat) {
+	vertex := s.reflections[spirv.ExecutionModelVertex]
+	if vertex == nil {
+		return
+	}
+	entry := vertex.EntryPoint(spirv.ExecutionModelVertex)
+	w.state.mutex.Lock()
+	attributes, ok := w.state.formats[format]
+	w.state.mutex.Unlock()
+	if entry == nil || !ok {
+		return
+	}
+	if err := entry.CheckVertexFormat(attributes); err != nil {
+		w.state.report(fmt.Errorf("rd.Interface.Renderer: %w", err))
+	}
+}
+
+// shaderSource tracks the shaders created from compiled source.
+type shaderSource struct {
+	rd.SPIRV
+	state *state
+}
+
+func (s shaderSource) Shader(name string) rd.Shader {
+	return &shader{Shader: s.SPIRV.Shader(name), state: s.state, reflections: s.state.reflect(s.SPIRV)}
+}
+
+// shader remembers the reflection of the SPIR-V it was compiled from.
+type shader struct {
+	rd.Shader
+	state       *state
+	reflections reflections
+}
+
+func (s *shader) Compile(data []byte) {
+	s.Shader.Compile(data)
+	s.reflections = s.state.binary(data)
+}
+
+// pushConstants returns the largest push constant block declared by any stage of the shader.
+func (s *shader) pushConstants() *spirv.Block {
+	var block *spirv.Block
+	for _, reflection := range s.reflections {
+		if reflection.PushConstants != nil && (block == nil || reflection.PushConstants.Size > block.Size) {
+			block = reflection.PushConstants
+		}
+	}
+	return block
+}
+
+// checkData checks that push constant data covers the push constant block of the shader.
+func (s *shader) checkData(method string, data []byte) {
+	if s == nil || s.reflections == nil {
+		return
+	}
+	block := s.pushConstants()
+	switch {
+	case block == nil && len(data) > 0:
+		s.state.report(fmt.Errorf("%s: %d bytes of push constants, but the shader has no push constant block", method, len(data)))
+	case block != nil && len(data) < block.Size:
+		s.state.report(fmt.Errorf("%s: %d bytes of push constants, but the shader's %s block is %d bytes", method, len(data), block.Name, block.Size))
+	}
+}
+
+// renderer remembers the shader it was created with.
+type renderer struct {
+	rd.Renderer
+	shader *shader
+}
+
+// processor remembers the shader it was created with.
+type processor struct {
+	rd.Processor
+	shader *shader
+}
+
+// drawing tracks the shader of the current renderer.
+type drawing struct {
+	rd.Drawing
+	state  *state
+	shader *shader
+}
+
+func (d *drawing) SetRenderer(r rd.Renderer) {
+	d.shader = nil
+	if wrapped, ok := r.(renderer); ok {
+		d.shader, r = wrapped.shader, wrapped.Renderer
+	}
+	d.Drawing.SetRenderer(r)
+}
+
+func (d *drawing) SetData(data []byte) {
+	d.shader.checkData("rd.Drawing.SetData", data)
+	d.Drawing.SetData(data)
+}
+
+// PushConstants returns the push constant block of the current renderer's shader, nil if it has none, and
+// whether the shader's reflection is known.
+func (d *drawing) PushConstants() (block *spirv.Block, known bool) {
+	if d.shader == nil || d.shader.reflections == nil {
+		return nil, false
+	}
+	return d.shader.pushConstants(), true
+}
+
+// compute tracks the shader of the current processor.
+type compute struct {
+	rd.Compute
+	state  *state
+	shader *shader
+}
+
+func (c *compute) SetProcessor(p rd.Processor) {
+	c.shader = nil
+	if wrapped, ok := p.(processor); ok {
+		c.shader, p = wrapped.shader, wrapped.Processor
+	}
+	c.Compute.SetProcessor(p)
+}
+
+func (c *compute) SetData(data []byte) {
+	c.shader.checkData("rd.Compute.SetData", data)
+	c.Compute.SetData(data)
+}
+
+// PushConstants returns the push constant block of the current processor's shader, nil if it has none, and
+// whether the shader's reflection is known.
+func (c *compute) PushConstants() (block *spirv.Block, known bool) {
+	if c.shader == nil || c.shader.reflections == nil {
+		return nil, false
+	}
+	return c.shader.pushConstants(), true
+}
@@ -0,0 +1,170 @@
+package rd
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+/*
+VertexFormatOf returns the vertex attributes described by the struct tags of T, which can be passed
+to [Interface.VertexFormat]. Each field of T that is fed to the vertex shader must have an rd tag
+with its location. The format is inferred from 32-bit float, int and uint fields (including vectors),
+otherwise it must be given as the name of a [DataFormat]. Add 'instance' to the tag to advance the
+attribute per instance, rather than per vertex.
+
+	type Vertex struct {
+		Position xy.Vector3 `rd:"location=0"`
+		Normal   xy.Vector3 `rd:"location=1"`
+		Color    [4]uint8   `rd:"location=2,format=R8G8B8A8_UNORM"`
+	}
+	attributes, err := rd.VertexFormatOf[Vertex]()
+
+Offsets follow the memory layout of T and the stride is the size of T, such that the result of
+[EncodeVertices] can be used directly. Returns an error if any field of T contains pointers (including
+slices, strings, interfaces, maps, channels and funcs), as their memory cannot be copied to the device.
+*/
+func VertexFormatOf[T any]() ([]VertexAttribute, error) {
+	t := reflect.TypeOf([0]T{}).Elem()
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("rd: vertex type %v is not a struct", t)
+	}
+	var attributes []VertexAttribute
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if kind, ok := pointerKind(field.Type); ok {
+			return nil, fmt.Errorf("rd: %v.%s contains a %v, which cannot be copied into a vertex buffer", t, field.Name, kind)
+		}
+		tag, ok := field.Tag.Lookup("rd")
+		if !ok {
+			continue
+		}
+		attribute := VertexAttribute{
+			Format:   DataFormatDefault,
+			Location: -1,
+			Offset:   int(field.Offset),
+			Stride:   int(t.Size()),
+		}
+		for _, option := range strings.Split(tag, ",") {
+			key, value, _ := strings.Cut(strings.TrimSpace(option), "=")
+			switch key {
+			case "location":
+				location, err := strconv.Atoi(value)
+				if err != nil || location < 0 {
+					return nil, fmt.Errorf("rd: %v.%s has an invalid location %q", t, field.Name, value)
+				}
+				attribute.Location = location
+			case "format":
+				format, ok := dataFormatsByName()[value]
+				if !ok {
+					return nil, fmt.Errorf("rd: %v.%s has an unknown format %q", t, field.Name, value)
+				}
+				attribute.Format = format
+			case "instance":
+				attribute.Frequency = AttributePerInstance
+			case "vertex":
+				attribute.Frequency = AttributePerVertex
+			default:
+				return nil, fmt.Errorf("rd: %v.%s has an unknown tag option %q", t, field.Name, key)
+			}
+		}
+		if attribute.Location < 0 {
+			return nil, fmt.Errorf("rd: %v.%s is missing a location", t, field.Name)
+		}
+		if attribute.Format == DataFormatDefault {
+			format, ok := vertexFormatFor(field.Type)
+			if !ok {
+				return nil, fmt.Errorf("rd: %v.%s requires a format, as it cannot be inferred from %v", t, field.Name, field.Type)
+			}
+			attribute.Format = format
+		}
+		if size := attribute.Format.Size(); size != int(field.Type.Size()) {
+			return nil, fmt.Errorf("rd: %v.%s is %d bytes, but %v is %d bytes", t, field.Name, field.Type.Size(), attribute.Format, size)
+		}
+		for _, existing := range attributes {
+			if existing.Location == attribute.Location {
+				return nil, fmt.Errorf("rd: %v.%s reuses location %d", t, field.Name, attribute.Location)
+			}
+		}
+		attributes = append(attributes, attribute)
+	}
+	return attributes, nil
+}
+
+// vertexFormatFor infers the format of a 32-bit float, int or uint field with up to four components.
+func vertexFormatFor(t reflect.Type) (DataFormat, bool) {
+	var kinds []reflect.Kind
+	var walk func(reflect.Type)
+	walk = func(t reflect.Type) {
+		switch t.Kind() {
+		case reflect.Array:
+			for i := 0; i < t.Len(); i++ {
+				walk(t.Elem())
+			}
+		case reflect.Struct:
+			for i := 0; i < t.NumField(); i++ {
+				walk(t.Field(i).Type)
+			}
+		default:
+			kinds = append(kinds, t.Kind())
+		}
+	}
+	walk(t)
+	if len(kinds) < 1 || len(kinds) > 4 {
+		return 0, false
+	}
+	for _, kind := range kinds[1:] {
+		if kind != kinds[0] {
+			return 0, false
+		}
+	}
+	var formats [4]DataFormat
+	switch kinds[0] {
+	case reflect.Float32:
+		formats = [4]DataFormat{DataFormat_R32_SFLOAT, DataFormat_R32G32_SFLOAT, DataFormat_R32G32B32_SFLOAT, DataFormat_R32G32B32A32_SFLOAT}
+	case reflect.Int32:
+		formats = [4]DataFormat{DataFormat_R32_SINT, DataFormat_R32G32_SINT, DataFormat_R32G32B32_SINT, DataFormat_R32G32B32A32_SINT}
+	case reflect.Uint32:
+		formats = [4]DataFormat{DataFormat_R32_UINT, DataFormat_R32G32_UINT, DataFormat_R32G32B32_UINT, DataFormat_R32G32B32A32_UINT}
+	default:
+		return 0, false
+	}
+	return formats[len(kinds)-1], true
+}
+
+// pointerKind returns the kind of the first pointer held by a value of type t, if any.
+func pointerKind(t reflect.Type) (reflect.Kind, bool) {
+	switch t.Kind() {
+	case reflect.Pointer, reflect.UnsafePointer, reflect.Slice, reflect.String, reflect.Interface,
+		reflect.Map, reflect.Chan, reflect.Func:
+		return t.Kind(), true
+	case reflect.Array:
+		return pointerKind(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if kind, ok := pointerKind(t.Field(i).Type); ok {
+				return kind, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// EncodeVertices returns a copy of the memory of the vertices, for use with [Interface.VertexBuffer]
+// alongside the attributes returned by [VertexFormatOf]. The rendering device is assumed to share the
+// byte order of the CPU.
+//
+// Panics if T contains pointers (including slices, strings, interfaces, maps, channels and funcs).
+func EncodeVertices[T any](vertices []T) []byte {
+	t := reflect.TypeOf([0]T{}).Elem()
+	if kind, ok := pointerKind(t); ok {
+		panic(fmt.Sprintf("rd: vertex type %v contains a %v, which cannot be copied into a vertex buffer", t, kind))
+	}
+	if len(vertices) == 0 {
+		return nil
+	}
+	size := int(unsafe.Sizeof(vertices[0])) * len(vertices)
+	return append([]byte(nil), unsafe.Slice((*byte)(unsafe.Pointer(unsafe.SliceData(vertices))), size)...)
+}